@@ -0,0 +1,239 @@
+package lionrouter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultRequestIDHeader is the header holding the request id if no header is set
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// AccessLogOptions
+// struct holds the options of the access log middleware
+type AccessLogOptions struct {
+	// Logger writes the log entries, slog.Default() if not set
+	Logger *slog.Logger
+
+	// Level of the log entries, responses with status >= 500 are logged with slog.LevelError
+	Level slog.Level
+
+	// SampleRate is the fraction of requests logged, e.g. 0.1, all requests are logged if not set
+	// Responses with status >= 500 are logged regardless of the sample rate
+	SampleRate float64
+
+	// Exclude holds request paths or route patterns which are not logged, e.g. /healthz
+	Exclude []string
+
+	// RequestIDHeader is the request or response header holding the request id, DefaultRequestIDHeader if not set
+	// Use the same header as Router.RequestIDHeader to log the same id as the request loggers
+	RequestIDHeader string
+}
+
+// AccessLog creates a middleware logging each request through log/slog once the response is completed
+// Wrap the router with the middleware to log the responses generated by the router like 404 and 405 as well,
+// the matched route of the innermost router is logged
+func AccessLog(options AccessLogOptions) Middleware {
+	logger := options.Logger
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	header := options.RequestIDHeader
+
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	exclude := make(map[string]bool, len(options.Exclude))
+
+	for _, e := range options.Exclude {
+		exclude[e] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if exclude[req.URL.Path] {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			// the capture records the route of the innermost router, including natively mounted sub-routers
+			ctx, capture := withRouteCapture(req.Context())
+
+			// the route is matched already if used as router middleware
+			if rc, ok := RouteContextFrom(ctx); ok && capture.route == nil {
+				capture.route = rc
+			}
+
+			rw := newResponseWriter(w)
+			start := time.Now()
+			served := false
+
+			defer func() {
+				status := rw.servedStatus(served)
+				level := options.Level
+
+				if status >= http.StatusInternalServerError {
+					level = slog.LevelError
+				} else if options.SampleRate > 0 && options.SampleRate < 1 && rand.Float64() >= options.SampleRate {
+					return
+				}
+
+				rc := capture.route
+				pattern := capture.label()
+
+				if exclude[pattern] {
+					return
+				}
+
+				attrs := []slog.Attr{
+					slog.String("method", req.Method),
+					slog.String("path", req.URL.Path),
+					slog.String("pattern", pattern),
+					slog.Int("status", status),
+					slog.Int64("bytes", rw.BytesWritten()),
+					slog.Duration("duration", time.Since(start)),
+					slog.String("remote_addr", req.RemoteAddr),
+					slog.String("request_id", requestID(req, rw, header)),
+					slog.String("proto", req.Proto),
+					slog.String("request_uri", req.URL.RequestURI()),
+					slog.String("referer", req.Referer()),
+					slog.String("user_agent", req.UserAgent()),
+				}
+
+				if rc != nil && len(rc.ParamList) > 0 {
+					params := make([]any, len(rc.ParamList))
+
+					for i, p := range rc.ParamList {
+						params[i] = slog.String(p.Key, p.Value)
+					}
+
+					attrs = append(attrs, slog.Group("params", params...))
+				}
+
+				logger.LogAttrs(ctx, level, "access", attrs...)
+			}()
+
+			next.ServeHTTP(rw.wrapped, req.WithContext(ctx))
+			served = true
+		})
+	}
+}
+
+// requestID retrieves the request id from the request header or the header of the response
+func requestID(req *http.Request, w http.ResponseWriter, header string) string {
+	if id := req.Header.Get(header); id != "" {
+		return id
+	}
+
+	return w.Header().Get(header)
+}
+
+// LogFormat
+// int defines the line format of a CommonLogHandler
+type LogFormat int
+
+const (
+	// log formats
+	CommonLogFormat   LogFormat = iota // Common Log Format
+	CombinedLogFormat                  // Combined Log Format, Common Log Format with referer and user agent
+)
+
+// CommonLogHandler
+// struct is a slog.Handler writing the entries of the access log middleware in the Common or Combined Log Format
+type CommonLogHandler struct {
+	format LogFormat
+
+	mutex *sync.Mutex
+	w     io.Writer
+
+	// attributes added through WithAttrs
+	attrs []slog.Attr
+}
+
+// NewCommonLogHandler creates a new handler writing the access log entries in the given format
+// returns handler instance
+func NewCommonLogHandler(w io.Writer, format LogFormat) *CommonLogHandler {
+	return &CommonLogHandler{
+		format: format,
+		mutex:  &sync.Mutex{},
+		w:      w,
+	}
+}
+
+// Enabled reports whether the handler handles records of the given level, all levels are handled
+func (h *CommonLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+// Handle writes the record as log line
+func (h *CommonLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	values := make(map[string]slog.Value, len(h.attrs)+record.NumAttrs())
+
+	for _, a := range h.attrs {
+		values[a.Key] = a.Value
+	}
+
+	record.Attrs(func(a slog.Attr) bool {
+		values[a.Key] = a.Value
+		return true
+	})
+
+	// missing values are logged as dash
+	field := func(key string) string {
+		if v, ok := values[key]; ok && v.String() != "" {
+			return v.String()
+		}
+
+		return "-"
+	}
+
+	bytes := field("bytes")
+
+	if bytes == "0" {
+		bytes = "-"
+	}
+
+	// client address without port
+	host := field("remote_addr")
+
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	line := fmt.Sprintf("%s - - [%s] %s %s %s", host, record.Time.Format("02/Jan/2006:15:04:05 -0700"),
+		strconv.Quote(field("method")+" "+field("request_uri")+" "+field("proto")), field("status"), bytes)
+
+	if h.format == CombinedLogFormat {
+		line += " " + strconv.Quote(field("referer")) + " " + strconv.Quote(field("user_agent"))
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	_, err := io.WriteString(h.w, line+"\n")
+
+	return err
+}
+
+// WithAttrs returns a handler using the given attributes for missing record attributes
+func (h *CommonLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+
+	return &handler
+}
+
+// WithGroup returns the handler, groups are not part of the log format
+func (h *CommonLogHandler) WithGroup(name string) slog.Handler {
+	return h
+}
@@ -0,0 +1,187 @@
+package lionrouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessLog(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	var output bytes.Buffer
+
+	handler := AccessLog(AccessLogOptions{
+		Logger:  slog.New(slog.NewJSONHandler(&output, nil)),
+		Exclude: []string{"/healthz", "/users/:id/ping"},
+	})(router)
+
+	subRouter.Get("/users/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(DefaultRequestIDHeader, "REQUEST_ID")
+		w.Write([]byte("USER"))
+	}))
+	router.Get("/healthz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	router.Get("/users/:id/ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	router.Post("/users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	router.Route("/tenants/:tenant", subRouter)
+	router.HandleMethodNotAllowed = true
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/tenants/acme/users/42"},
+		{http.MethodGet, "/healthz"},
+		{http.MethodGet, "/users/42/ping"},
+		{http.MethodGet, "/unknown"},
+		{http.MethodGet, "/users"},
+	}
+
+	for _, request := range requests {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(request.method, request.path, nil))
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("logged %d entries and should be 3:\n%s", len(lines), output.String())
+	}
+
+	expected := []map[string]any{
+		{"method": "GET", "path": "/tenants/acme/users/42", "pattern": "/tenants/:tenant/users/:id", "status": 200.0, "bytes": 4.0, "request_id": "REQUEST_ID", "params": map[string]any{"tenant": "acme", "id": "42"}},
+		{"path": "/unknown", "pattern": "NotFound", "status": 404.0},
+		{"path": "/users", "pattern": "MethodNotAllowed", "status": 405.0},
+	}
+
+	for i, line := range lines {
+		var entry map[string]any
+
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log entry: %v", err)
+		}
+
+		for k, v := range expected[i] {
+			if got, _ := json.Marshal(entry[k]); string(got) != mustJSON(v) {
+				t.Errorf("entry %d field '%s' is %s and should be %s", i, k, got, mustJSON(v))
+			}
+		}
+
+		if entry["msg"] != "access" || entry["remote_addr"] != "192.0.2.1:1234" || entry["duration"] == nil {
+			t.Errorf("entry %d is incomplete: %s", i, line)
+		}
+	}
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	var output bytes.Buffer
+
+	router.Use(AccessLog(AccessLogOptions{Logger: slog.New(slog.NewJSONHandler(&output, nil))}))
+	router.Get("/health", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	subRouter.Get("/users/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	router.Route("/api/:v", subRouter)
+
+	for _, path := range []string{"/api/1/users/7", "/health"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("logged %d entries and should be 2:\n%s", len(lines), output.String())
+	}
+
+	// the route of the innermost router is logged if used as router middleware
+	expected := []map[string]any{
+		{"pattern": "/api/:v/users/:id", "params": map[string]any{"v": "1", "id": "7"}},
+		{"pattern": "/health"},
+	}
+
+	for i, line := range lines {
+		var entry map[string]any
+
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log entry: %v", err)
+		}
+
+		for k, v := range expected[i] {
+			if got, _ := json.Marshal(entry[k]); string(got) != mustJSON(v) {
+				t.Errorf("entry %d field '%s' is %s and should be %s", i, k, got, mustJSON(v))
+			}
+		}
+	}
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	var output bytes.Buffer
+
+	handler := AccessLog(AccessLogOptions{
+		Logger:     slog.New(slog.NewJSONHandler(&output, nil)),
+		SampleRate: 1e-9,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	for i := 0; i < 100; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/error", nil))
+
+	if lines := strings.Split(strings.TrimSpace(output.String()), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"level":"ERROR"`) {
+		t.Errorf("only the server error should be logged, got:\n%s", output.String())
+	}
+}
+
+func TestCommonLogHandler(t *testing.T) {
+	var output bytes.Buffer
+
+	tests := []struct {
+		format   LogFormat
+		expected string
+	}{
+		{CommonLogFormat, `192.0.2.1 - - [10/Oct/2000:13:55:36 +0000] "GET /users/42?x=1 HTTP/1.1" 200 4`},
+		{CombinedLogFormat, `192.0.2.1 - - [10/Oct/2000:13:55:36 +0000] "GET /users/42?x=1 HTTP/1.1" 200 4 "https://example.com/" "agent \"1\""`},
+	}
+
+	for _, test := range tests {
+		output.Reset()
+
+		record := slog.NewRecord(time.Date(2000, 10, 10, 13, 55, 36, 0, time.UTC), slog.LevelInfo, "access", 0)
+		record.AddAttrs(
+			slog.String("method", "GET"),
+			slog.String("request_uri", "/users/42?x=1"),
+			slog.String("proto", "HTTP/1.1"),
+			slog.Int("status", 200),
+			slog.Int64("bytes", 4),
+			slog.String("referer", "https://example.com/"),
+			slog.String("user_agent", `agent "1"`),
+		)
+
+		handler := NewCommonLogHandler(&output, test.format).WithAttrs([]slog.Attr{slog.String("remote_addr", "192.0.2.1:1234")})
+
+		if err := handler.Handle(context.Background(), record); err != nil {
+			t.Fatalf("handle failed: %v", err)
+		}
+
+		if line := strings.TrimSpace(output.String()); line != test.expected {
+			t.Errorf("log line is '%s' and should be '%s'", line, test.expected)
+		}
+	}
+}
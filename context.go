@@ -0,0 +1,173 @@
+package lionrouter
+
+import "context"
+
+// contextKey
+// int is the private type of the context keys, no other package can collide with them
+type contextKey int
+
+const (
+	// context iota keys
+	contextRouteKey       contextKey = iota // RouteContext context key
+	contextSuggestionsKey                   // suggested routes context key
+	contextCaptureKey                       // route capture context key
+	contextSpanKey                          // span context key of the MemoryTracer
+	contextLoggerKey                        // request logger context key
+)
+
+const (
+	// route labels of requests without matching route, used for metrics and tracing
+	unmatchedNotFound         = "NotFound"
+	unmatchedMethodNotAllowed = "MethodNotAllowed"
+)
+
+// MatchedRoute
+// struct describes the route matched for a request
+type MatchedRoute struct {
+	// Pattern of the route including the patterns of sub-router mounts, e.g. /tenants/:tenant/users/:id
+	Pattern string
+
+	// Name of the route, empty if not named
+	Name string
+
+	// Method of the request
+	Method string
+
+	// MountPath holds the path prefix matched by parent routers
+	MountPath string
+}
+
+// RouteContext
+// struct holds the routing state of a request, a single instance is stored in the request context per router
+type RouteContext struct {
+	// matched route, for a sub-router mount the MountPath includes the mount prefix
+	MatchedRoute
+
+	// Params captured by the route and all parent routers
+	Params RouterParam
+
+	// ParamList holds the params of the route and all parent routers in capture order
+	ParamList ParamList
+
+	// Parent holds the route context of the parent router, nil if not passed through another router
+	Parent *RouteContext
+
+	// route is a sub-router mount
+	mount bool
+
+	// error handler of the innermost router assigning one, nil if none assigned
+	errorHandler ErrorHandler
+
+	// default error handler responds with problem details
+	problemDetails bool
+
+	// request logger of the route, built once retrieved through Logger
+	log requestLogger
+}
+
+// newRouteContext creates the route context for the given node and the params captured by the router
+// The pattern is prefixed with the pattern of the parent router if mounted as sub-router
+func newRouteContext(ctx context.Context, n *node, method string, params RouterParam, list ParamList) *RouteContext {
+	rc := &RouteContext{
+		MatchedRoute: MatchedRoute{
+			Pattern: n.route,
+			Method:  method,
+		},
+		Params:    params,
+		ParamList: list,
+	}
+
+	if rc.Params == nil {
+		rc.Params = make(RouterParam)
+	}
+
+	if n.leaf != nil {
+		rc.Name = n.leaf.getName(method)
+	}
+
+	if parent, ok := RouteContextFrom(ctx); ok {
+		rc.Parent = parent
+		rc.MountPath = parent.MountPath
+		rc.errorHandler = parent.errorHandler
+
+		// merge params of parent routers
+		mergeParams(rc.Params, parent.Params)
+
+		if len(parent.ParamList) > 0 {
+			rc.ParamList = append(append(ParamList{}, parent.ParamList...), list...)
+		}
+
+		if parent.mount {
+			rc.Pattern = parent.Pattern + rc.Pattern
+		}
+	}
+
+	return rc
+}
+
+// RouteContextFrom extracts the route context of the innermost router from the given context
+// It returns the route context and true or nil and false if no router passed the request
+func RouteContextFrom(ctx context.Context) (*RouteContext, bool) {
+	rc, ok := ctx.Value(contextRouteKey).(*RouteContext)
+
+	return rc, ok
+}
+
+// Matched extracts the matched route from the given context
+// It returns the matched route and true or an empty route and false if no route matched
+func Matched(ctx context.Context) (MatchedRoute, bool) {
+	// get route context from context
+	rc, ok := RouteContextFrom(ctx)
+
+	if ok {
+		return rc.MatchedRoute, true
+	}
+
+	return MatchedRoute{}, false
+}
+
+// Pattern extracts the pattern of the matched route from the given context
+// It returns the pattern or an empty string if no route matched
+func Pattern(ctx context.Context) string {
+	route, _ := Matched(ctx)
+
+	return route.Pattern
+}
+
+// routeCapture
+// struct records the route matched by the innermost router for routers observing the request
+type routeCapture struct {
+	// route context of the innermost router, nil if no route matched
+	route *RouteContext
+
+	// route label if no route matched
+	unmatched string
+}
+
+// withRouteCapture adds a route capture to the given context, the capture of parent routers is reused
+// returns the context and the capture
+func withRouteCapture(ctx context.Context) (context.Context, *routeCapture) {
+	if c, ok := ctx.Value(contextCaptureKey).(*routeCapture); ok {
+		return ctx, c
+	}
+
+	c := &routeCapture{unmatched: unmatchedNotFound}
+
+	return context.WithValue(ctx, contextCaptureKey, c), c
+}
+
+// captureRoute records the route context or the label of the unmatched request in the capture of the given context if present
+func captureRoute(ctx context.Context, rc *RouteContext, unmatched string) {
+	if c, ok := ctx.Value(contextCaptureKey).(*routeCapture); ok {
+		c.route, c.unmatched = rc, unmatched
+	}
+}
+
+// label retrieves the route label of the captured request
+func (c *routeCapture) label() string {
+	if c.route != nil {
+		return c.route.Pattern
+	}
+
+	return c.unmatched
+}
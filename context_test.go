@@ -0,0 +1,186 @@
+package lionrouter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMatchedRoute(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	routeHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, ok := Matched(r.Context())
+
+			if !ok {
+				t.Errorf("no matched route for '%s'", r.URL.Path)
+			}
+
+			w.Write([]byte(route.Method + " " + route.Pattern + " " + route.Name + " " + route.MountPath))
+		})
+	}
+
+	patternMiddleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Pattern", Pattern(r.Context()))
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	router.Use(patternMiddleware)
+	router.Get("/users/:id", routeHandler()).Name("user")
+	router.Route("/tenants/:tenant", subRouter)
+	router.Route("/static", routeHandler())
+	subRouter.Post("/projects/:project", routeHandler()).Name("project")
+
+	tests := []struct {
+		method   string
+		path     string
+		pattern  string
+		expected string
+	}{
+		{http.MethodGet, "/users/42", "/users/:id", "GET /users/:id user "},
+		{http.MethodPost, "/tenants/acme/projects/x", "/tenants/:tenant", "POST /tenants/:tenant/projects/:project project /tenants/acme"},
+		{http.MethodGet, "/static/app.css", "/static", "GET /static  /static"},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(test.method, test.path, nil))
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("route '%s' responded with '%s' and should be '%s'", test.path, body, test.expected)
+		}
+
+		if pattern := w.Header().Get("X-Pattern"); pattern != test.pattern {
+			t.Errorf("pattern in middleware for '%s' is '%s' and should be '%s'", test.path, pattern, test.pattern)
+		}
+	}
+
+	if routes := router.Routes(); routes[len(routes)-1].Name != "user" {
+		t.Errorf("route name of '%s' is '%s' and should be 'user'", routes[len(routes)-1].Pattern, routes[len(routes)-1].Name)
+	}
+
+	if _, ok := Matched(httptest.NewRequest(http.MethodGet, "/", nil).Context()); ok {
+		t.Error("matched route should not exist without router")
+	}
+}
+
+func TestPathValue(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	valueHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.PathValue("tenant") + " " + r.PathValue("id") + " " + r.PathValue("path") + " " + Param(r.Context(), "path")))
+		})
+	}
+
+	router.Route("/tenants/:tenant", subRouter)
+	router.Get("/files/:id/*path", valueHandler())
+	subRouter.Get("/users/:id", valueHandler())
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/tenants/acme/users/42", "acme 42  "},
+		{"/files/42/a/b.txt", " 42 a/b.txt /a/b.txt"},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("route '%s' responded with '%s' and should be '%s'", test.path, body, test.expected)
+		}
+	}
+
+	// path values of sub-routers must not leak into the request of parent middleware
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			if id := r.PathValue("id"); id != "" {
+				t.Errorf("path value of sub-router leaked into parent request: %s", id)
+			}
+		})
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tenants/acme/users/5", nil))
+}
+
+func BenchmarkPathValue(b *testing.B) {
+	router := New()
+	subRouter := New()
+
+	valueHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.PathValue("f")
+	})
+
+	router.Get("/users/:id/files/:f", valueHandler)
+	router.Route("/tenants/:tenant", subRouter)
+	subRouter.Get("/users/:id/files/:f", valueHandler)
+
+	for _, path := range []string{"/users/42/files/a.txt", "/tenants/acme/users/42/files/a.txt"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+
+		// typical browser request headers
+		for _, h := range []string{"Accept", "Accept-Encoding", "Accept-Language", "Cache-Control", "Connection", "Cookie",
+			"Referer", "User-Agent", "X-Forwarded-For", "X-Forwarded-Proto", "X-Request-Id", "Authorization"} {
+			req.Header.Set(h, "value")
+		}
+
+		b.Run(path, func(b *testing.B) {
+			w := httptest.NewRecorder()
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				router.ServeHTTP(w, req)
+			}
+		})
+	}
+}
+
+func TestRouteContext(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	contextHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rc, ok := RouteContextFrom(r.Context())
+
+			if !ok {
+				t.Fatal("no route context found")
+			}
+
+			if rc.Parent == nil || rc.Parent.Pattern != "/tenants/:tenant" || rc.Parent.Params["id"] != "" {
+				t.Errorf("parent route context mismatch: %+v", rc.Parent)
+			}
+
+			w.Write([]byte(rc.Pattern + " " + rc.MountPath + " " + rc.Params["tenant"] + " " + rc.Params["id"]))
+		})
+	}
+
+	subRouter.Get("/users/:id", contextHandler())
+	router.Route("/tenants/:tenant", subRouter)
+
+	// untyped keys of other packages must not collide
+	req := httptest.NewRequest(http.MethodGet, "/tenants/acme/users/42", nil)
+	req = req.WithContext(context.WithValue(req.Context(), 0, RouterParam{"tenant": "foo"}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if body, expected := w.Body.String(), "/tenants/:tenant/users/:id /tenants/acme acme 42"; body != expected {
+		t.Errorf("route responded with '%s' and should be '%s'", body, expected)
+	}
+
+	if _, ok := Params(req.Context()); ok {
+		t.Error("params should not be found for untyped context key")
+	}
+}
@@ -1,11 +1,61 @@
 package lionrouter
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrUnknownHTTPMethod = errors.New("unknown or unsupported http method")
-	ErrNilHandler        = errors.New("nil handler cannot be assigned")
-	ErrAlreadyAssigned   = errors.New("cannot reassign handler")
-	ErrAssignment        = errors.New("cannot assign child/leaf to node")
-	ErrNoHandler         = errors.New("no handler for http method assigned")
+	ErrUnknownHTTPMethod  = errors.New("unknown or unsupported http method")
+	ErrNilHandler         = errors.New("nil handler cannot be assigned")
+	ErrAlreadyAssigned    = errors.New("cannot reassign handler")
+	ErrAssignment         = errors.New("cannot assign child/leaf to node")
+	ErrNoHandler          = errors.New("no handler for http method assigned")
+	ErrMiddlewareNotFound = errors.New("no middleware with given name assigned")
+	ErrMiddlewareName     = errors.New("middleware name empty or already in use")
+	ErrRouteName          = errors.New("route name empty or already in use")
+	ErrUnknownRoute       = errors.New("no route with given name")
+	ErrURLParams          = errors.New("url params don't match route")
+	ErrParamConflict      = errors.New("param name conflicts with assigned route")
+	ErrDuplicateParam     = errors.New("duplicate param name in route")
+	ErrNamedPath          = errors.New("named path must be the last key of route")
+	ErrFrozen             = errors.New("router is frozen, no further assignments possible")
+	ErrShadowed           = errors.New("static key shadowed by named key or path")
+	ErrPattern            = errors.New("invalid route pattern")
 )
+
+// RouteError
+// struct describes a failed route assignment, the cause can be checked through errors.Is
+type RouteError struct {
+	// Method of the route, empty for sub-routers
+	Method string
+
+	// Pattern of the route
+	Pattern string
+
+	// Conflict holds the pattern of the assigned route the route conflicts with, if any
+	Conflict string
+
+	// Err holds the cause
+	Err error
+}
+
+// Error returns the error message including method and pattern
+func (e *RouteError) Error() string {
+	route := e.Pattern
+
+	if e.Method != "" {
+		route = e.Method + " " + route
+	}
+
+	if e.Conflict != "" {
+		return fmt.Sprintf("%s: %s (conflicts with '%s')", route, e.Err.Error(), e.Conflict)
+	}
+
+	return fmt.Sprintf("%s: %s", route, e.Err.Error())
+}
+
+// Unwrap returns the cause
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
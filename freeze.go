@@ -0,0 +1,116 @@
+package lionrouter
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Freeze validates and compacts the routes, the router is read-only afterwards
+// Further assignments of routes, sub-routers, middleware and route names fail with ErrFrozen
+// Mounted lionrouter sub-routers are frozen as well
+// returns the errors reported by Validate and the errors found in the route tree
+func (r *Router) Freeze() error {
+	return errors.Join(r.Validate(), r.freeze())
+}
+
+// freeze compacts and freezes the trees of the router and its mounted lionrouter sub-routers
+// returns the errors found in the trees
+func (r *Router) freeze() error {
+	r.trie.frozen = true
+	r.trie.compact()
+
+	errs := r.trie.validate()
+
+	r.trie.walk(func(pattern string, n *node) error {
+		if child, ok := n.router.(*Router); ok {
+			errs = append(errs, child.freeze())
+		}
+
+		return nil
+	})
+
+	return errors.Join(errs...)
+}
+
+// Frozen reports whether the router is read-only
+func (r *Router) Frozen() bool {
+	return r.trie.frozen
+}
+
+// compact removes empty leaves and branches without any assigned handler
+// and trims the remaining nodes to their minimal size
+func (t *trie) compact() {
+	compactNode(t.root)
+}
+
+// compactNode compacts the given node and its descendants
+// returns whether any handler is assigned to the node or its descendants
+func compactNode(n *node) bool {
+	for k, child := range n.children {
+		if !compactNode(child) {
+			delete(n.children, k)
+		}
+	}
+
+	if len(n.children) == 0 {
+		n.children = nil
+	}
+
+	if n.wildcard != nil && !compactNode(n.wildcard) {
+		n.wildcard = nil
+	}
+
+	if n.leaf != nil {
+		// remove unset handlers at the end
+		handler := n.leaf.handler
+
+		for len(handler) > 0 && handler[len(handler)-1] == nil {
+			handler = handler[:len(handler)-1]
+		}
+
+		if len(handler) == 0 {
+			n.leaf = nil
+		} else {
+			n.leaf.handler = append(make([]http.Handler, 0, len(handler)), handler...)
+		}
+	}
+
+	return n.leaf != nil || n.router != nil || n.children != nil || n.wildcard != nil || n.notFound != nil || n.methodNotAllowed != nil
+}
+
+// validate checks the tree for routes which can never be reached
+// returns a *RouteError for each sub-router with children and each shadowed route
+func (t *trie) validate() []error {
+	var errs []error
+
+	check := func(pattern string, n *node) error {
+		// sub-routers hide all descendants
+		if n.router != nil && (n.leaf != nil || n.children != nil || n.wildcard != nil) {
+			conflict := n.wildcard
+
+			for _, child := range n.children {
+				conflict = child
+			}
+
+			if conflict == nil {
+				conflict = n
+			}
+
+			errs = append(errs, &RouteError{"", n.pattern, conflict.assignedPattern(), ErrAssignment})
+		}
+
+		// named keys and paths are matched before static keys
+		if n.wildcard != nil {
+			for _, child := range n.children {
+				errs = append(errs, &RouteError{"", child.assignedPattern(), n.wildcard.assignedPattern(), ErrShadowed})
+			}
+		}
+
+		return nil
+	}
+
+	check("", t.root)
+	t.walk(check)
+
+	return errs
+}
@@ -0,0 +1,149 @@
+package lionrouter
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// do nothing
+		})
+	}
+
+	router := New()
+	subRouter := New()
+
+	router.Get("/", testHandler())
+	router.Get("/users/:id", testHandler())
+	router.Get("/files/*path", testHandler())
+	router.Route("/sub", subRouter)
+	subRouter.Get("/foo", testHandler())
+
+	// leave an empty leaf behind
+	router.Get("/removed/route", testHandler())
+	router.trie.root.children["removed"].children["route"].leaf.unsetHandler(http.MethodGet)
+
+	if err := router.Freeze(); err != nil {
+		t.Errorf("freeze failed --> %s", err.Error())
+	}
+
+	if !router.Frozen() || !subRouter.Frozen() {
+		t.Error("router and sub-router should be frozen")
+	}
+
+	if router.trie.root.children["removed"] != nil {
+		t.Error("branch without handler should be removed")
+	}
+
+	if m := router.trie.get(http.MethodGet, splitPath("/users/42")); m.handler == nil {
+		t.Error("route should be retrievable after freeze")
+	}
+
+	if _, err := router.Handle(http.MethodGet, "/foo", testHandler()); !errors.Is(err, ErrFrozen) {
+		t.Errorf("assignment after freeze should fail, error is: %v", err)
+	}
+
+	if _, err := subRouter.Handle(http.MethodGet, "/bar", testHandler()); !errors.Is(err, ErrFrozen) {
+		t.Errorf("assignment to sub-router after freeze should fail, error is: %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r != ErrFrozen {
+			t.Errorf("middleware assignment after freeze should panic, recovered: %v", r)
+		}
+	}()
+
+	router.Use(func(next http.Handler) http.Handler { return next })
+}
+
+func TestFreezeShadowed(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// do nothing
+		})
+	}
+
+	router := New()
+	router.Get("/users/:id", testHandler())
+	router.Get("/users/me", testHandler())
+	router.Get("/:page", testHandler())
+	router.Get("/about", testHandler())
+
+	// shadowed routes are assigned, they are found by the tree validation only
+	if err := router.Validate(); err != nil {
+		t.Errorf("shadowed routes should not be assignment errors, error is: %v", err)
+	}
+
+	errs := router.trie.validate()
+
+	if len(errs) != 3 {
+		t.Fatalf("tree validation should report 3 shadowed routes, reported: %v", errs)
+	}
+
+	for _, e := range errs {
+		var routeErr *RouteError
+
+		if !errors.As(e, &routeErr) || !errors.Is(e, ErrShadowed) || routeErr.Conflict == "" {
+			t.Errorf("tree validation should report shadowed route with conflict, error is: %v", e)
+		}
+	}
+
+	err := router.Freeze()
+
+	if !errors.Is(err, ErrShadowed) {
+		t.Fatalf("freeze should report shadowed routes, error is: %v", err)
+	}
+
+	var routeErr *RouteError
+
+	if !errors.As(err, &routeErr) {
+		t.Fatalf("freeze should report route errors, error is: %v", err)
+	}
+
+	shadowed := 0
+
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		for _, e := range e.(interface{ Unwrap() []error }).Unwrap() {
+			if errors.Is(e, ErrShadowed) {
+				shadowed++
+			}
+		}
+	}
+
+	if shadowed != 3 {
+		t.Errorf("freeze should report 3 shadowed routes, reported: %d --> %v", shadowed, err)
+	}
+}
+
+func TestFreezeMountChildren(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// do nothing
+		})
+	}
+
+	router := New()
+	router.Get("/api/users", testHandler())
+
+	// assignments refuse sub-routers with children, set the sub-router on the tree directly
+	router.trie.find(parsePath("/api")).router = testHandler()
+
+	errs := router.trie.validate()
+
+	var routeErr *RouteError
+
+	if len(errs) != 1 || !errors.As(errs[0], &routeErr) || !errors.Is(errs[0], ErrAssignment) {
+		t.Fatalf("tree validation should report the sub-router with children, reported: %v", errs)
+	}
+
+	if routeErr.Conflict != "/api/users" {
+		t.Errorf("route error should hold the hidden route, error is: %v", routeErr)
+	}
+
+	if err := router.Freeze(); !errors.Is(err, ErrAssignment) {
+		t.Errorf("freeze should report the sub-router with children, error is: %v", err)
+	}
+}
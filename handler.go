@@ -0,0 +1,116 @@
+package lionrouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// HandlerFunc
+// func is a handler returning an error, the error is passed through the ErrorHandler of the router
+// It implements the http.Handler interface and can be assigned like any other handler
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls the handler and passes a returned error through the error handler found in the request context
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		errorHandler(r.Context())(w, r, err)
+	}
+}
+
+// ErrorHandler
+// func maps an error returned by a HandlerFunc to a response
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// HTTPError
+// struct is an error carrying the status code of the response
+type HTTPError struct {
+	// Status code of the response, e.g. http.StatusBadRequest
+	Status int
+
+	// Err is the cause, its message is sent for client errors, nil to send the status text
+	Err error
+}
+
+// NewHTTPError creates a new error for the given status code and cause
+// returns the error
+func NewHTTPError(status int, err error) *HTTPError {
+	return &HTTPError{status, err}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+	}
+
+	return fmt.Sprintf("%d %s: %v", e.Status, http.StatusText(e.Status), e.Err)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the status code of the response
+func (e *HTTPError) StatusCode() int {
+	return e.Status
+}
+
+// errorHandler retrieves the error handler of the innermost router from the given context
+func errorHandler(ctx context.Context) ErrorHandler {
+	if rc, ok := RouteContextFrom(ctx); ok && rc.errorHandler != nil {
+		// use custom error handler
+		return rc.errorHandler
+	}
+
+	// use default error handler
+	return defaultErrorHandler
+}
+
+// defaultErrorHandler maps the error to a plain text response
+// Errors implementing StatusCode() int like HTTPError set the status code, any other error or an invalid code results in 500
+// The message of client errors is sent, server errors are logged and answered with the status text only
+// The response is written as problem details if enabled by the router
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	// client is gone, nobody reads the response
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	status := http.StatusInternalServerError
+
+	var statusErr interface{ StatusCode() int }
+
+	if errors.As(err, &statusErr) {
+		// invalid codes would panic on WriteHeader
+		if code := statusErr.StatusCode(); code >= 100 && code <= 999 {
+			status = code
+		}
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		status = http.StatusServiceUnavailable
+	}
+
+	message := ""
+
+	if status >= http.StatusInternalServerError {
+		Logger(r.Context()).LogAttrs(r.Context(), slog.LevelError, "error serving request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Any("error", err),
+		)
+	} else if httpErr := (*HTTPError)(nil); errors.As(err, &httpErr) && httpErr.Err != nil {
+		message = httpErr.Err.Error()
+	}
+
+	if rc, ok := RouteContextFrom(r.Context()); ok && rc.problemDetails {
+		WriteProblem(w, r, Problem{Status: status, Detail: message})
+		return
+	}
+
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	http.Error(w, message, status)
+}
@@ -0,0 +1,132 @@
+package lionrouter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerFunc(t *testing.T) {
+	router := New()
+
+	var output bytes.Buffer
+	router.Logger = slog.New(slog.NewTextHandler(&output, nil))
+
+	errorFunc := func(err error) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			return err
+		}
+	}
+
+	router.Get("/ok", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("OK"))
+		return nil
+	}))
+	router.Get("/notfound", errorFunc(NewHTTPError(http.StatusNotFound, errors.New("user not found"))))
+	router.Get("/wrapped", errorFunc(fmt.Errorf("load user: %w", NewHTTPError(http.StatusBadRequest, nil))))
+	router.Get("/internal", errorFunc(errors.New("database down")))
+	router.Get("/timeout", errorFunc(fmt.Errorf("query: %w", context.DeadlineExceeded)))
+	router.Get("/canceled", errorFunc(context.Canceled))
+	router.Get("/invalid", errorFunc(NewHTTPError(0, errors.New("no status"))))
+
+	tests := []struct {
+		path     string
+		status   int
+		expected string
+	}{
+		{"/ok", http.StatusOK, "OK"},
+		{"/notfound", http.StatusNotFound, "user not found\n"},
+		{"/wrapped", http.StatusBadRequest, "Bad Request\n"},
+		{"/internal", http.StatusInternalServerError, "Internal Server Error\n"},
+		{"/timeout", http.StatusServiceUnavailable, "Service Unavailable\n"},
+		{"/canceled", http.StatusOK, ""},
+		{"/invalid", http.StatusInternalServerError, "Internal Server Error\n"},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if w.Code != test.status {
+			t.Errorf("route '%s' should be %d, router responded with: %d", test.path, test.status, w.Code)
+		}
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("route '%s' responded with '%s' and should be '%s'", test.path, body, test.expected)
+		}
+	}
+
+	if !bytes.Contains(output.Bytes(), []byte("error=\"database down\"")) || !bytes.Contains(output.Bytes(), []byte("pattern=/internal")) {
+		t.Errorf("server errors should be logged through the request logger, got: %s", output.String())
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	router := New()
+	subRouter := New()
+	nestedRouter := New()
+
+	errorHandler := func(name string) ErrorHandler {
+		return func(w http.ResponseWriter, r *http.Request, err error) {
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte(name + " " + err.Error()))
+		}
+	}
+
+	errorFunc := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("ERROR")
+	})
+
+	router.Get("/error", errorFunc)
+	subRouter.Get("/error", errorFunc)
+	nestedRouter.Get("/error", errorFunc)
+	subRouter.Route("/nested", nestedRouter)
+	router.Route("/sub", subRouter)
+
+	router.ErrorHandler = errorHandler("PARENT")
+	nestedRouter.ErrorHandler = errorHandler("NESTED")
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/error", "PARENT ERROR"},
+		{"/sub/error", "PARENT ERROR"},
+		{"/sub/nested/error", "NESTED ERROR"},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if w.Code != http.StatusTeapot {
+			t.Errorf("route '%s' should be %d, router responded with: %d", test.path, http.StatusTeapot, w.Code)
+		}
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("route '%s' responded with '%s' and should be '%s'", test.path, body, test.expected)
+		}
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	cause := errors.New("cause")
+	err := fmt.Errorf("wrapped: %w", NewHTTPError(http.StatusConflict, cause))
+
+	if !errors.Is(err, cause) {
+		t.Errorf("error should unwrap to '%v'", cause)
+	}
+
+	if msg := err.Error(); msg != "wrapped: 409 Conflict: cause" {
+		t.Errorf("error message is '%s'", msg)
+	}
+
+	if msg := NewHTTPError(http.StatusGone, nil).Error(); msg != "410 Gone" {
+		t.Errorf("error message is '%s'", msg)
+	}
+}
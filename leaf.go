@@ -14,8 +14,22 @@ const (
 	leafHandlerOptions
 )
 
+// leafMethods maps the leaf handler index to the http method
+var leafMethods = [...]string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodHead,
+	http.MethodOptions,
+}
+
 type leaf struct {
 	handler []http.Handler
+
+	// route names by handler index
+	names []string
 }
 
 func newLeaf() *leaf {
@@ -102,3 +116,40 @@ func (l *leaf) getHandler(method string) (http.Handler, error) {
 
 	return nil, nil
 }
+
+func (l *leaf) methods() []string {
+	var methods []string
+
+	for i, h := range l.handler {
+		if h != nil {
+			methods = append(methods, leafMethods[i])
+		}
+	}
+
+	return methods
+}
+
+func (l *leaf) setName(method string, name string) error {
+	handlerMethod, err := handlerMethodFromString(method)
+
+	if err != nil {
+		return err
+	}
+
+	if len(l.names) <= handlerMethod {
+		l.names = append(l.names, make([]string, handlerMethod-len(l.names)+1)...)
+	}
+
+	l.names[handlerMethod] = name
+	return nil
+}
+
+func (l *leaf) getName(method string) string {
+	handlerMethod, err := handlerMethodFromString(method)
+
+	if err != nil || len(l.names) <= handlerMethod {
+		return ""
+	}
+
+	return l.names[handlerMethod]
+}
@@ -87,3 +87,26 @@ func TestLeafHandler(t *testing.T) {
 		}
 	}
 }
+
+func TestLeafMethods(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// do nothing
+		})
+	}
+
+	testLeaf := newLeaf()
+
+	if methods := testLeaf.methods(); len(methods) != 0 {
+		t.Errorf("new leaf should have no methods, methods are: %v", methods)
+	}
+
+	testLeaf.addHandler(http.MethodPut, testHandler())
+	testLeaf.addHandler(http.MethodGet, testHandler())
+
+	methods := testLeaf.methods()
+
+	if len(methods) != 2 || methods[0] != http.MethodGet || methods[1] != http.MethodPut {
+		t.Errorf("leaf methods are %v and should be [GET PUT]", methods)
+	}
+}
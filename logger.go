@@ -0,0 +1,156 @@
+package lionrouter
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// requestLogger
+// struct holds the inputs of the logger of a request, the logger is built once it is retrieved
+type requestLogger struct {
+	once   sync.Once
+	logger *slog.Logger
+
+	// logger of the router, nil if not set
+	base *slog.Logger
+
+	// request header and the name of the header holding the request id
+	header   http.Header
+	idHeader string
+}
+
+// unmatchedLogger
+// struct holds the logger of a request without matching route
+type unmatchedLogger struct {
+	requestLogger
+
+	// route context of parent routers when the request was not matched, nil if not mounted
+	parent *RouteContext
+
+	// route label of the request
+	unmatched string
+}
+
+// Logger extracts the logger of the request from the given context
+// The logger holds the request id, the matched pattern, the mount path of parent routers and the params
+// It returns the logger or slog.Default() if no router passed the request
+func Logger(ctx context.Context) *slog.Logger {
+	if l := loggerFrom(ctx); l != nil {
+		return l
+	}
+
+	return slog.Default()
+}
+
+// loggerFrom extracts the logger of the request from the given context
+// returns the logger or nil if not set
+func loggerFrom(ctx context.Context) *slog.Logger {
+	rc, matched := RouteContextFrom(ctx)
+
+	// route contexts added after the request was not matched belong to handlers of inner routers
+	if l, ok := ctx.Value(contextLoggerKey).(*unmatchedLogger); ok && (!matched || rc == l.parent) {
+		return l.get(nil, l.unmatched)
+	}
+
+	if matched && rc.log.base != nil {
+		return rc.log.get(rc, "")
+	}
+
+	return nil
+}
+
+// get builds the logger on first use
+func (l *requestLogger) get(route *RouteContext, unmatched string) *slog.Logger {
+	l.once.Do(func() {
+		var attrs []any
+
+		if id := l.header.Get(l.idHeader); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+
+		if route == nil {
+			l.logger = l.base.With(append(attrs, slog.String("pattern", unmatched))...)
+			return
+		}
+
+		attrs = append(attrs, slog.String("pattern", route.Pattern))
+
+		if route.MountPath != "" {
+			attrs = append(attrs, slog.String("mount_path", route.MountPath))
+		}
+
+		if len(route.ParamList) > 0 {
+			params := make([]any, 0, len(route.ParamList))
+			added := make(map[string]bool, len(route.ParamList))
+
+			// each name once with the value kept by the params map
+			for _, p := range route.ParamList {
+				if !added[p.Key] {
+					added[p.Key] = true
+					params = append(params, slog.String(p.Key, route.Params[p.Key]))
+				}
+			}
+
+			attrs = append(attrs, slog.Group("params", params...))
+		}
+
+		l.logger = l.base.With(attrs...)
+	})
+
+	return l.logger
+}
+
+// setRequestLogger sets the inputs of the request logger
+func (r *Router) setRequestLogger(l *requestLogger, req *http.Request, scope *mountScope) {
+	l.base = r.logger(scope)
+	l.header = req.Header
+	l.idHeader = r.requestIDHeader(scope)
+}
+
+// withUnmatchedLogger adds the request logger of a request without matching route to the given context
+// returns the context holding the logger
+func (r *Router) withUnmatchedLogger(ctx context.Context, req *http.Request, scope *mountScope, unmatched string) context.Context {
+	l := &unmatchedLogger{unmatched: unmatched}
+	l.parent, _ = RouteContextFrom(ctx)
+	r.setRequestLogger(&l.requestLogger, req, scope)
+
+	return context.WithValue(ctx, contextLoggerKey, l)
+}
+
+// loggerFor retrieves the logger of the request from the given context,
+// the base logger of the router if the request logger is not set yet
+func (r *Router) loggerFor(ctx context.Context, scope *mountScope) *slog.Logger {
+	if l := loggerFrom(ctx); l != nil {
+		return l
+	}
+
+	return r.logger(scope)
+}
+
+// logger retrieves the base logger of the router or the one inherited from parent routers
+func (r *Router) logger(scope *mountScope) *slog.Logger {
+	if r.Logger != nil {
+		return r.Logger
+	}
+
+	if scope != nil && scope.logger != nil {
+		return scope.logger
+	}
+
+	return slog.Default()
+}
+
+// requestIDHeader retrieves the request id header of the router or the one inherited from parent routers
+func (r *Router) requestIDHeader(scope *mountScope) string {
+	if r.RequestIDHeader != "" {
+		return r.RequestIDHeader
+	}
+
+	if scope != nil && scope.requestIDHeader != "" {
+		return scope.requestIDHeader
+	}
+
+	return DefaultRequestIDHeader
+}
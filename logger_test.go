@@ -0,0 +1,77 @@
+package lionrouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	var output bytes.Buffer
+	router.Logger = slog.New(slog.NewJSONHandler(&output, nil))
+
+	logHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Logger(r.Context()).Info("HANDLER")
+	})
+
+	subRouter.Get("/users/:id", logHandler)
+	router.Get("/status", logHandler)
+	router.Route("/tenants/:tenant", subRouter)
+	router.NotFoundHandler = logHandler
+
+	tests := []struct {
+		path     string
+		expected map[string]any
+	}{
+		{"/status", map[string]any{"request_id": "REQUEST_ID", "pattern": "/status"}},
+		{"/tenants/acme/users/42", map[string]any{"request_id": "REQUEST_ID", "pattern": "/tenants/:tenant/users/:id", "mount_path": "/tenants/acme", "params": map[string]any{"tenant": "acme", "id": "42"}}},
+		{"/unknown", map[string]any{"request_id": "REQUEST_ID", "pattern": "NotFound"}},
+	}
+
+	for _, test := range tests {
+		output.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		req.Header.Set(DefaultRequestIDHeader, "REQUEST_ID")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]any
+
+		if err := json.Unmarshal(output.Bytes(), &entry); err != nil {
+			t.Fatalf("route '%s' logged invalid entry: %v", test.path, err)
+		}
+
+		delete(entry, "time")
+		delete(entry, "level")
+		delete(entry, "msg")
+
+		if !reflect.DeepEqual(entry, test.expected) {
+			t.Errorf("route '%s' logged %v and should be %v", test.path, entry, test.expected)
+		}
+	}
+
+	// sub-routers use the request id header of their parent router
+	router.RequestIDHeader = "X-Correlation-ID"
+	output.Reset()
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/acme/users/42", nil)
+	req.Header.Set(DefaultRequestIDHeader, "REQUEST_ID")
+	req.Header.Set("X-Correlation-ID", "CORRELATION_ID")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !bytes.Contains(output.Bytes(), []byte(`"request_id":"CORRELATION_ID"`)) {
+		t.Errorf("request id should be taken from the configured header, logged: %s", output.String())
+	}
+
+	if Logger(context.Background()) != slog.Default() {
+		t.Errorf("logger without router should be the default logger")
+	}
+}
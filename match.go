@@ -0,0 +1,76 @@
+package lionrouter
+
+import "net/http"
+
+// RouteMatch
+// struct describes the route matched for a method and path
+type RouteMatch struct {
+	// Pattern of the route including the patterns of sub-router mounts, e.g. /tenants/:tenant/users/:id
+	Pattern string
+
+	// Params captured by the route and all sub-router mounts
+	Params RouterParam
+
+	// Handler assigned for the method without middleware, nil if method not allowed
+	// For mounted handlers which are no lionrouter instance the mounted handler itself
+	Handler http.Handler
+
+	// Mounts holds the patterns of all sub-router mounts passed, outermost first
+	Mounts []string
+
+	// MethodAllowed is false if the path matches a route without handler for the method
+	MethodAllowed bool
+}
+
+// Match looks up the route for given method and path without serving a request
+// returns the match and true if the path matches a route, even if the method is not allowed
+func (r *Router) Match(method string, path string) (RouteMatch, bool) {
+	return r.match(method, splitPath(path), RouteMatch{})
+}
+
+// match looks up the route for given method and split path
+// parent holds the match of the parent routers if mounted natively
+func (r *Router) match(method string, parsed []string, parent RouteMatch) (RouteMatch, bool) {
+	m := r.trie.get(method, parsed)
+
+	// no route matches, intermediate nodes without handler don't match either
+	if m.node == nil || (m.node.leaf == nil && m.node.router == nil) {
+		return RouteMatch{}, false
+	}
+
+	match := RouteMatch{
+		Pattern:       parent.Pattern + m.node.route,
+		Params:        m.params,
+		Handler:       m.handler,
+		Mounts:        parent.Mounts,
+		MethodAllowed: m.handler != nil,
+	}
+
+	if match.Params == nil {
+		match.Params = make(RouterParam)
+	}
+
+	// merge params of parent routers
+	mergeParams(match.Params, parent.Params)
+
+	// no sub-router matched
+	if m.depth == 0 {
+		return match, true
+	}
+
+	match.Mounts = append(append([]string{}, parent.Mounts...), match.Pattern)
+
+	// walk into lionrouter sub-routers
+	if child, ok := m.handler.(*Router); ok {
+		rest := parsed[m.depth:]
+
+		if len(rest) == 0 {
+			rest = []string{""}
+		}
+
+		match.Handler = nil
+		return child.match(method, rest, match)
+	}
+
+	return match, true
+}
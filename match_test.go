@@ -0,0 +1,82 @@
+package lionrouter
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// do nothing
+		})
+	}
+
+	router := New()
+	subRouter := New()
+
+	router.Get("/", testHandler())
+	router.Get("/users/:id", testHandler())
+	router.Get("/files/*path", testHandler())
+	router.Route("/static", testHandler())
+	router.Route("/tenants/:tenant", subRouter)
+	subRouter.Get("/", testHandler())
+	subRouter.Post("/users/:id", testHandler())
+
+	tests := []struct {
+		method  string
+		path    string
+		found   bool
+		allowed bool
+		pattern string
+		params  RouterParam
+		mounts  int
+	}{
+		{http.MethodGet, "/", true, true, "/", RouterParam{}, 0},
+		{http.MethodGet, "/users/42", true, true, "/users/:id", RouterParam{"id": "42"}, 0},
+		{http.MethodPost, "/users/42", true, false, "/users/:id", RouterParam{}, 0},
+		{http.MethodGet, "/files/a/b.txt", true, true, "/files/*path", RouterParam{"path": "/a/b.txt"}, 0},
+		{http.MethodGet, "/static/app.css", true, true, "/static", RouterParam{}, 1},
+		{http.MethodGet, "/tenants/acme", true, true, "/tenants/:tenant/", RouterParam{"tenant": "acme"}, 1},
+		{http.MethodPost, "/tenants/acme/users/42", true, true, "/tenants/:tenant/users/:id", RouterParam{"tenant": "acme", "id": "42"}, 1},
+		{http.MethodGet, "/tenants/acme/users/42", true, false, "/tenants/:tenant/users/:id", RouterParam{"tenant": "acme"}, 1},
+		{http.MethodGet, "/users", false, false, "", nil, 0},
+		{http.MethodGet, "/unknown", false, false, "", nil, 0},
+		{http.MethodGet, "/tenants/acme/unknown", false, false, "", nil, 0},
+	}
+
+	for _, test := range tests {
+		match, ok := router.Match(test.method, test.path)
+
+		if ok != test.found {
+			t.Errorf("match for '%s %s' should be %t", test.method, test.path, test.found)
+			continue
+		}
+
+		if match.MethodAllowed != test.allowed || (match.Handler != nil) != test.allowed {
+			t.Errorf("method for '%s %s' should be allowed: %t", test.method, test.path, test.allowed)
+		}
+
+		if match.Pattern != test.pattern {
+			t.Errorf("pattern for '%s %s' is '%s' and should be '%s'", test.method, test.path, match.Pattern, test.pattern)
+		}
+
+		if len(match.Mounts) != test.mounts {
+			t.Errorf("mounts for '%s %s' are %v, count should be %d", test.method, test.path, match.Mounts, test.mounts)
+		}
+
+		if !test.allowed {
+			continue
+		}
+
+		if len(match.Params) != len(test.params) {
+			t.Errorf("params for '%s %s' are %v and should be %v", test.method, test.path, match.Params, test.params)
+		}
+
+		for k, v := range test.params {
+			if match.Params[k] != v {
+				t.Errorf("param '%s' for '%s %s' is '%s' and should be '%s'", k, test.method, test.path, match.Params[k], v)
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package lionrouter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds of the latency histogram in seconds used if no buckets are given
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// method label of requests with unknown method
+const metricsOtherMethod = "OTHER"
+
+// Metrics
+// struct counts requests, status classes and latencies per route pattern and method
+// It implements the http.Handler interface and serves the metrics in the Prometheus text format
+type Metrics struct {
+	// upper bounds of the latency histogram in seconds, sorted ascending
+	buckets []float64
+
+	mutex  sync.Mutex
+	routes map[metricsKey]*routeMetrics
+}
+
+// metricsKey
+// struct holds the labels of a route
+type metricsKey struct {
+	method string
+	route  string
+}
+
+// routeMetrics
+// struct holds the metrics of a route
+type routeMetrics struct {
+	// request count per status class, 1xx to 5xx
+	status [5]uint64
+
+	// latency histogram, count per bucket without the +Inf bucket
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// NewMetrics creates a new metrics instance with the given histogram buckets in seconds,
+// DefaultBuckets are used if no buckets are given
+// returns metrics instance
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+
+	return &Metrics{
+		buckets: buckets,
+		routes:  make(map[metricsKey]*routeMetrics),
+	}
+}
+
+// observe records a request for the given route pattern
+func (m *Metrics) observe(method string, route string, status int, duration time.Duration) {
+	// unknown methods would create a label value per client input
+	if _, err := handlerMethodFromString(method); err != nil {
+		method = metricsOtherMethod
+	}
+
+	key := metricsKey{method, route}
+	seconds := duration.Seconds()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	rm := m.routes[key]
+
+	if rm == nil {
+		rm = &routeMetrics{buckets: make([]uint64, len(m.buckets))}
+		m.routes[key] = rm
+	}
+
+	if class := status/100 - 1; class >= 0 && class < len(rm.status) {
+		rm.status[class]++
+	}
+
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			rm.buckets[i]++
+		}
+	}
+
+	rm.sum += seconds
+	rm.count++
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	buffer := bufio.NewWriter(w)
+	m.write(buffer)
+	buffer.Flush()
+}
+
+// write writes the metrics in the Prometheus text format, routes sorted by pattern and method
+func (m *Metrics) write(w *bufio.Writer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	keys := make([]metricsKey, 0, len(m.routes))
+
+	for k := range m.routes {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+
+		return keys[i].method < keys[j].method
+	})
+
+	w.WriteString("# HELP lionrouter_requests_total Count of requests by route, method and status class.\n")
+	w.WriteString("# TYPE lionrouter_requests_total counter\n")
+
+	for _, k := range keys {
+		for class, count := range m.routes[k].status {
+			if count > 0 {
+				fmt.Fprintf(w, "lionrouter_requests_total{method=\"%s\",route=\"%s\",status=\"%dxx\"} %d\n", escapeLabel(k.method), escapeLabel(k.route), class+1, count)
+			}
+		}
+	}
+
+	w.WriteString("# HELP lionrouter_request_duration_seconds Latency of requests by route and method.\n")
+	w.WriteString("# TYPE lionrouter_request_duration_seconds histogram\n")
+
+	for _, k := range keys {
+		rm := m.routes[k]
+		labels := fmt.Sprintf("method=\"%s\",route=\"%s\"", escapeLabel(k.method), escapeLabel(k.route))
+
+		for i, bound := range m.buckets {
+			fmt.Fprintf(w, "lionrouter_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, strconv.FormatFloat(bound, 'g', -1, 64), rm.buckets[i])
+		}
+
+		fmt.Fprintf(w, "lionrouter_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, rm.count)
+		fmt.Fprintf(w, "lionrouter_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(rm.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "lionrouter_request_duration_seconds_count{%s} %d\n", labels, rm.count)
+	}
+}
+
+// labelEscaper escapes label values for the Prometheus text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+// observeMetrics wraps the response writer to observe the request for the metrics of the router
+// returns the wrapped writer, the context holding the route capture and the function to call once the request is served
+func (r *Router) observeMetrics(w http.ResponseWriter, ctx context.Context, method string) (http.ResponseWriter, context.Context, func(served bool)) {
+	rw := newResponseWriter(w)
+	ctx, capture := withRouteCapture(ctx)
+	start := time.Now()
+
+	return rw.wrapped, ctx, func(served bool) {
+		r.Metrics.observe(method, capture.label(), rw.servedStatus(served), time.Since(start))
+	}
+}
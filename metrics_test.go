@@ -0,0 +1,114 @@
+package lionrouter
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	router := New()
+	subRouter := New()
+	metrics := NewMetrics(0.5, 0.1)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	handler := func(status int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		})
+	}
+
+	router.Get("/users/:id", handler(http.StatusOK))
+	router.Post("/users", handler(http.StatusCreated))
+	router.Get("/panic", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("PANIC")
+	}))
+	subRouter.Get("/projects/:project", handler(http.StatusBadRequest))
+	router.Route("/tenants/:tenant", subRouter)
+	router.Route("/metrics", metrics)
+
+	router.Metrics = metrics
+	router.HandleMethodNotAllowed = true
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/users/1"},
+		{http.MethodGet, "/users/2"},
+		{http.MethodPost, "/users"},
+		{http.MethodGet, "/users"},
+		{http.MethodGet, "/unknown"},
+		{http.MethodGet, "/tenants/acme/projects/x"},
+		{http.MethodGet, "/tenants/acme/unknown"},
+		{http.MethodGet, "/panic"},
+		{"PROPFIND", "/users/1"},
+	}
+
+	for _, request := range requests {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(request.method, request.path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	expected := []string{
+		"# TYPE lionrouter_requests_total counter\n",
+		`lionrouter_requests_total{method="GET",route="/users/:id",status="2xx"} 2` + "\n",
+		`lionrouter_requests_total{method="POST",route="/users",status="2xx"} 1` + "\n",
+		`lionrouter_requests_total{method="GET",route="MethodNotAllowed",status="4xx"} 1` + "\n",
+		`lionrouter_requests_total{method="GET",route="NotFound",status="4xx"} 2` + "\n",
+		`lionrouter_requests_total{method="GET",route="/tenants/:tenant/projects/:project",status="4xx"} 1` + "\n",
+		`lionrouter_requests_total{method="GET",route="/panic",status="5xx"} 1` + "\n",
+		`lionrouter_requests_total{method="OTHER",route="MethodNotAllowed",status="4xx"} 1` + "\n",
+		"# TYPE lionrouter_request_duration_seconds histogram\n",
+		`lionrouter_request_duration_seconds_bucket{method="GET",route="/users/:id",le="0.1"} 2` + "\n",
+		`lionrouter_request_duration_seconds_bucket{method="GET",route="/users/:id",le="0.5"} 2` + "\n",
+		`lionrouter_request_duration_seconds_bucket{method="GET",route="/users/:id",le="+Inf"} 2` + "\n",
+		`lionrouter_request_duration_seconds_count{method="GET",route="/users/:id"} 2` + "\n",
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(body, e) {
+			t.Errorf("metrics should contain '%s', got:\n%s", strings.TrimSpace(e), body)
+		}
+	}
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("content type is '%s'", ct)
+	}
+}
+
+func TestMetricsObserve(t *testing.T) {
+	metrics := NewMetrics(0.1, 1)
+
+	metrics.observe(http.MethodGet, `/a"b\c`, http.StatusOK, 50*time.Millisecond)
+	metrics.observe(http.MethodGet, `/a"b\c`, http.StatusOK, 500*time.Millisecond)
+	metrics.observe(http.MethodGet, `/a"b\c`, http.StatusOK, 5*time.Second)
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := w.Body.String()
+
+	expected := []string{
+		`lionrouter_requests_total{method="GET",route="/a\"b\\c",status="2xx"} 3`,
+		`lionrouter_request_duration_seconds_bucket{method="GET",route="/a\"b\\c",le="0.1"} 1`,
+		`lionrouter_request_duration_seconds_bucket{method="GET",route="/a\"b\\c",le="1"} 2`,
+		`lionrouter_request_duration_seconds_bucket{method="GET",route="/a\"b\\c",le="+Inf"} 3`,
+		`lionrouter_request_duration_seconds_sum{method="GET",route="/a\"b\\c"} 5.55`,
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(body, e+"\n") {
+			t.Errorf("metrics should contain '%s', got:\n%s", e, body)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package lionrouter
+
+import "net/http"
+
+// Middleware
+// func(http.Handler) http.Handler wraps a handler using the default go middleware technique
+type Middleware func(http.Handler) http.Handler
+
+// MiddlewareOrder
+// int defines in which order the middleware stack is executed
+type MiddlewareOrder int
+
+const (
+	// MiddlewareInnermostFirst wraps the handler in registration order,
+	// the first registered middleware is executed last (default for backward compatibility)
+	MiddlewareInnermostFirst MiddlewareOrder = iota
+
+	// MiddlewareOutermostFirst executes the middleware in registration order,
+	// the first registered middleware is executed first
+	MiddlewareOutermostFirst
+)
+
+// middlewareEntry holds a single middleware of the stack and its optional name
+type middlewareEntry struct {
+	name string
+	fn   Middleware
+}
+
+// UseNamed assigns a named middleware to the router instance
+// The name can be referenced by UseBefore and UseAfter
+func (r *Router) UseNamed(name string, middleware func(http.Handler) http.Handler) {
+	err := r.collect(r.insertMiddleware(len(r.middleware), name, middleware))
+
+	if err != nil && !r.CollectErrors {
+		panic(err)
+	}
+}
+
+// UseBefore assigns a named middleware which is executed right before the middleware named target
+func (r *Router) UseBefore(target string, name string, middleware func(http.Handler) http.Handler) {
+	err := r.collect(r.useRelative(target, name, middleware, true))
+
+	if err != nil && !r.CollectErrors {
+		panic(err)
+	}
+}
+
+// UseAfter assigns a named middleware which is executed right after the middleware named target
+func (r *Router) UseAfter(target string, name string, middleware func(http.Handler) http.Handler) {
+	err := r.collect(r.useRelative(target, name, middleware, false))
+
+	if err != nil && !r.CollectErrors {
+		panic(err)
+	}
+}
+
+// useRelative inserts the middleware relative to the target in execution order
+// returns error if target not found or name is invalid
+func (r *Router) useRelative(target string, name string, middleware func(http.Handler) http.Handler, before bool) error {
+	i := r.middlewareIndex(target)
+
+	if i < 0 {
+		return ErrMiddlewareNotFound
+	}
+
+	// the stack is stored in registration order, which is reversed for legacy execution order
+	if before == (r.MiddlewareOrder == MiddlewareInnermostFirst) {
+		i++
+	}
+
+	return r.insertMiddleware(i, name, middleware)
+}
+
+// insertMiddleware inserts a named middleware at the given position of the stack
+// returns error if name is empty or already in use
+func (r *Router) insertMiddleware(i int, name string, middleware func(http.Handler) http.Handler) error {
+	if r.trie.frozen {
+		return ErrFrozen
+	}
+
+	if middleware == nil {
+		return ErrNilHandler
+	}
+
+	if name == "" || r.middlewareIndex(name) >= 0 {
+		return ErrMiddlewareName
+	}
+
+	r.middleware = append(r.middleware, middlewareEntry{})
+	copy(r.middleware[i+1:], r.middleware[i:])
+	r.middleware[i] = middlewareEntry{name, middleware}
+
+	return nil
+}
+
+// middlewareIndex looks up the named middleware
+// returns the position in the stack or -1
+func (r *Router) middlewareIndex(name string) int {
+	if name == "" {
+		return -1
+	}
+
+	for i, m := range r.middleware {
+		if m.name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// middlewareStack returns the middleware in execution order, outermost first
+func (r *Router) middlewareStack() []Middleware {
+	entries := r.middlewareEntries()
+	stack := make([]Middleware, len(entries))
+
+	for i, m := range entries {
+		stack[i] = m.fn
+	}
+
+	return stack
+}
+
+// middlewareEntries retrieves the middleware entries in execution order, outermost first
+func (r *Router) middlewareEntries() []middlewareEntry {
+	entries := make([]middlewareEntry, len(r.middleware))
+
+	for i, m := range r.middleware {
+		if r.MiddlewareOrder == MiddlewareOutermostFirst {
+			entries[i] = m
+		} else {
+			entries[len(entries)-1-i] = m
+		}
+	}
+
+	return entries
+}
+
+// middlewareChain builds the middleware stack, each middleware is wrapped with a span if tracing
+// returns the http.Handler stack
+func (r *Router) middlewareChain(next http.Handler, tracer Tracer) http.Handler {
+	entries := r.middlewareEntries()
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		next = entries[i].fn(next)
+
+		if tracer != nil {
+			next = traceMiddleware(tracer, middlewareName(entries[i], i), next)
+		}
+	}
+
+	return next
+}
@@ -0,0 +1,95 @@
+package lionrouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	orderMiddleware := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(name))
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("h"))
+		})
+	}
+
+	tests := []struct {
+		order    MiddlewareOrder
+		setup    func(r *Router)
+		expected string
+	}{
+		{MiddlewareInnermostFirst, func(r *Router) {
+			r.Use(orderMiddleware("a"), orderMiddleware("b"), orderMiddleware("c"))
+		}, "cbah"},
+		{MiddlewareOutermostFirst, func(r *Router) {
+			r.Use(orderMiddleware("a"), orderMiddleware("b"), orderMiddleware("c"))
+		}, "abch"},
+		{MiddlewareOutermostFirst, func(r *Router) {
+			r.UseNamed("a", orderMiddleware("a"))
+			r.UseNamed("c", orderMiddleware("c"))
+			r.UseBefore("c", "b", orderMiddleware("b"))
+			r.UseAfter("c", "d", orderMiddleware("d"))
+			r.UseBefore("a", "x", orderMiddleware("x"))
+		}, "xabcdh"},
+		{MiddlewareInnermostFirst, func(r *Router) {
+			r.UseNamed("a", orderMiddleware("a"))
+			r.UseNamed("c", orderMiddleware("c"))
+			r.UseBefore("c", "b", orderMiddleware("b"))
+			r.UseAfter("c", "d", orderMiddleware("d"))
+			r.UseAfter("a", "x", orderMiddleware("x"))
+		}, "bcdaxh"},
+	}
+
+	for i, test := range tests {
+		router := New()
+		router.MiddlewareOrder = test.order
+		test.setup(router)
+		router.Get("/", testHandler())
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("test %d: middleware order is '%s' and should be '%s'", i, body, test.expected)
+		}
+	}
+}
+
+func TestMiddlewareNamedErrors(t *testing.T) {
+	noop := func(next http.Handler) http.Handler {
+		return next
+	}
+
+	router := New()
+	router.UseNamed("foo", noop)
+
+	if err := router.insertMiddleware(0, "foo", noop); err != ErrMiddlewareName {
+		t.Errorf("duplicate middleware name should fail, error is: %v", err)
+	}
+
+	if err := router.insertMiddleware(0, "", noop); err != ErrMiddlewareName {
+		t.Errorf("empty middleware name should fail, error is: %v", err)
+	}
+
+	if err := router.useRelative("bar", "baz", noop, true); err != ErrMiddlewareNotFound {
+		t.Errorf("unknown target middleware should fail, error is: %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(error).Error(), "no middleware") {
+			t.Errorf("UseAfter with unknown target should panic, recovered: %v", r)
+		}
+	}()
+
+	router.UseAfter("bar", "baz", noop)
+}
@@ -0,0 +1,122 @@
+package lionrouter
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// mountScope holds the state passed from a parent router to a natively mounted sub-router
+type mountScope struct {
+	// handlers inherited from parent routers
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+
+	// 405 responses enabled by parent routers
+	handleMethodNotAllowed bool
+
+	// problem details enabled by parent routers
+	problemDetails bool
+
+	// route suggestions enabled by parent routers
+	suggestRoutes bool
+
+	// tracer of parent routers, the dispatch span is started by the outermost router tracing the request
+	tracer Tracer
+
+	// base logger and request id header of parent routers
+	logger          *slog.Logger
+	requestIDHeader string
+}
+
+// MountPath extracts the path prefix matched by parent routers from the given context
+// It returns the mount path or an empty string if not mounted
+func MountPath(ctx context.Context) string {
+	// get route context from context
+	rc, ok := RouteContextFrom(ctx)
+
+	if ok {
+		return rc.MountPath
+	}
+
+	return ""
+}
+
+// mountRouter returns a handler dispatching the request straight into the trie of the sub-router
+// The request path is passed through untouched, the sub-router walks the remaining split path only
+// m is the match of the mount in the parent router, its prefix handlers are passed to the sub-router
+func (r *Router) mountRouter(child *Router, path string, parsed []string, m *trieMatch, scope *mountScope) http.Handler {
+	// sub-router root is requested
+	if len(parsed) == 0 {
+		parsed = []string{""}
+	}
+
+	// pass assigned fallback handlers to sub-router, the sub-router uses its own default handlers otherwise
+	childScope := &mountScope{
+		notFound:               r.assignedNotFound(m, scope),
+		methodNotAllowed:       r.assignedMethodNotAllowed(m, scope),
+		handleMethodNotAllowed: r.HandleMethodNotAllowed || m.methodNotAllowed != nil || (scope != nil && scope.handleMethodNotAllowed),
+		problemDetails:         r.problemDetails(scope),
+		suggestRoutes:          r.suggestRoutes(scope),
+		tracer:                 r.tracer(scope),
+		logger:                 r.logger(scope),
+		requestIDHeader:        r.requestIDHeader(scope),
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		child.serve(w, req, path, parsed, childScope)
+	})
+}
+
+// pathPrefix cuts the given count of keys from the start of a path
+// returns the path prefix without trailing slash
+func pathPrefix(path string, depth int) string {
+	i := 0
+
+	// skip leading slash
+	if strings.HasPrefix(path, "/") {
+		i = 1
+	}
+
+	for ; depth > 0; depth-- {
+		next := strings.IndexByte(path[i:], '/')
+
+		// last key reached, whole path is prefix
+		if next < 0 {
+			return path
+		}
+
+		i += next + 1
+	}
+
+	return path[:i-1]
+}
+
+// stripMount strips the matched mount prefix from the request path
+// before the request is passed through the mounted handler
+func stripMount(prefix string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// shallow copy request and url to keep the original request untouched
+		r := new(http.Request)
+		*r = *req
+		r.URL = new(url.URL)
+		*r.URL = *req.URL
+
+		r.URL.Path = strings.TrimPrefix(req.URL.Path, prefix)
+
+		// strip escaped path if set, same key count as the decoded prefix
+		if req.URL.RawPath != "" {
+			rawPrefix := pathPrefix(req.URL.RawPath, strings.Count(prefix, "/"))
+
+			if unescaped, err := url.PathUnescape(rawPrefix); err == nil && unescaped == prefix {
+				r.URL.RawPath = strings.TrimPrefix(req.URL.RawPath, rawPrefix)
+			} else {
+				r.URL.RawPath = ""
+			}
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,151 @@
+package lionrouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPathPrefix(t *testing.T) {
+	tests := []struct {
+		path     string
+		depth    int
+		expected string
+	}{
+		{"/hello/world/foo", 2, "/hello/world"},
+		{"/hello/world/", 2, "/hello/world"},
+		{"/hello/world", 2, "/hello/world"},
+		{"/hello/world", 1, "/hello"},
+		{"hello/world", 1, "hello"},
+	}
+
+	for _, test := range tests {
+		if prefix := pathPrefix(test.path, test.depth); prefix != test.expected {
+			t.Errorf("prefix of '%s' with depth %d is '%s' and should be '%s'", test.path, test.depth, prefix, test.expected)
+		}
+	}
+}
+
+func TestParamMount(t *testing.T) {
+	router := New()
+
+	mountHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(Param(r.Context(), "tenant") + " " + r.URL.Path + " " + r.URL.RawPath))
+		})
+	}
+
+	router.Route("/tenants/:tenant/", mountHandler())
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/tenants/acme/users/1", "acme /users/1 "},
+		{"/tenants/acme/", "acme / "},
+		{"/tenants/acme/files/a%2Fb", "acme /files/a/b /files/a%2Fb"},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("route '%s' responded with '%s' and should be '%s'", test.path, body, test.expected)
+		}
+	}
+}
+
+func TestMountParamMerge(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	paramHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			params, _ := Params(r.Context())
+			w.Write([]byte(params["tenant"] + " " + params["id"]))
+		})
+	}
+
+	subRouter.Get("/users/:id", paramHandler())
+	subRouter.Get("/tenant/:tenant", paramHandler())
+	router.Route("/tenants/:tenant", subRouter)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/tenants/acme/users/42", "acme 42"},
+		{"/tenants/acme/tenant/other", "other "},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("route '%s' responded with '%s' and should be '%s'", test.path, body, test.expected)
+		}
+	}
+}
+
+func TestNativeMount(t *testing.T) {
+	router := New()
+	subRouter := New()
+	nestedRouter := New()
+
+	pathHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.URL.Path + " " + MountPath(r.Context())))
+		})
+	}
+
+	notFoundHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("PARENT_404"))
+		})
+	}
+
+	nestedRouter.Get("/files/*file", pathHandler())
+	subRouter.Get("/users/:id", pathHandler())
+	subRouter.Post("/users", pathHandler())
+	subRouter.Route("/projects/:project", nestedRouter)
+	router.Route("/tenants/:tenant", subRouter)
+
+	router.NotFoundHandler = notFoundHandler()
+	router.HandleMethodNotAllowed = true
+
+	tests := []struct {
+		method   string
+		path     string
+		status   int
+		expected string
+	}{
+		{http.MethodGet, "/tenants/acme/users/42", http.StatusOK, "/tenants/acme/users/42 /tenants/acme"},
+		{http.MethodGet, "/tenants/acme/projects/x/files/a/b.txt", http.StatusOK, "/tenants/acme/projects/x/files/a/b.txt /tenants/acme/projects/x"},
+		{http.MethodGet, "/tenants/acme/unknown", http.StatusNotFound, "PARENT_404"},
+		{http.MethodGet, "/tenants/acme/projects/x/unknown", http.StatusNotFound, "PARENT_404"},
+		{http.MethodGet, "/tenants/acme/users", http.StatusMethodNotAllowed, "405 - Method not allowed!"},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(test.method, test.path, nil))
+
+		if w.Code != test.status {
+			t.Errorf("route '%s' should be %d, router responded with: %d", test.path, test.status, w.Code)
+		}
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("route '%s' responded with '%s' and should be '%s'", test.path, body, test.expected)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/acme/users", nil))
+
+	if allow := w.Header().Get("Allow"); allow != http.MethodPost {
+		t.Errorf("allow header is '%s' and should be '%s'", allow, http.MethodPost)
+	}
+}
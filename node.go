@@ -5,11 +5,21 @@ import "net/http"
 type node struct {
 	key *pathKey
 
+	// pattern of the first route assigned to or passing through the node
+	pattern string
+
+	// route pattern of the node in canonical notation, e.g. /users/:id
+	route string
+
 	leaf     *leaf
 	wildcard *node
 	children map[string]*node
 
 	router http.Handler
+
+	// 404 and 405 handlers for the subtree, nil if not assigned
+	notFound         http.Handler
+	methodNotAllowed http.Handler
 }
 
 func newNode(key *pathKey) *node {
@@ -17,3 +27,20 @@ func newNode(key *pathKey) *node {
 		key: key,
 	}
 }
+
+// assignedPattern retrieves the pattern of a route assigned to the node or its descendants
+func (n *node) assignedPattern() string {
+	if n.leaf != nil || n.router != nil {
+		return n.pattern
+	}
+
+	for _, child := range n.children {
+		return child.assignedPattern()
+	}
+
+	if n.wildcard != nil {
+		return n.wildcard.assignedPattern()
+	}
+
+	return n.pattern
+}
@@ -0,0 +1,74 @@
+package lionrouter
+
+import "context"
+
+// ParamEntry
+// struct holds a single captured param
+type ParamEntry struct {
+	Key   string
+	Value string
+}
+
+// ParamList
+// []ParamEntry holds the params in capture order, outermost router first
+// A name can occur repeatedly if captured by different mounted routers
+type ParamList []ParamEntry
+
+// Get returns the value of the param captured last for the given name, like the RouterParam map
+// It returns the value or an empty string
+func (p ParamList) Get(name string) string {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].Key == name {
+			return p[i].Value
+		}
+	}
+
+	return ""
+}
+
+// Index returns the value of the param at the given position
+// It returns the value or an empty string if out of range
+func (p ParamList) Index(i int) string {
+	if i < 0 || i >= len(p) {
+		return ""
+	}
+
+	return p[i].Value
+}
+
+// All returns all values captured for the given name in capture order
+func (p ParamList) All(name string) []string {
+	var values []string
+
+	for _, e := range p {
+		if e.Key == name {
+			values = append(values, e.Value)
+		}
+	}
+
+	return values
+}
+
+// Map converts the params to a RouterParam map, the value captured last is kept for repeated names
+func (p ParamList) Map() RouterParam {
+	params := make(RouterParam, len(p))
+
+	for _, e := range p {
+		params[e.Key] = e.Value
+	}
+
+	return params
+}
+
+// OrderedParams extracts the params in capture order from the given context, including the params of parent routers
+// It returns the params or nil
+func OrderedParams(ctx context.Context) ParamList {
+	// get route context from context
+	rc, ok := RouteContextFrom(ctx)
+
+	if ok {
+		return rc.ParamList
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package lionrouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParamList(t *testing.T) {
+	params := ParamList{{"id", "1"}, {"name", "foo"}, {"id", "2"}}
+
+	if v := params.Get("id"); v != "2" {
+		t.Errorf("param 'id' is '%s' and should be '2'", v)
+	}
+
+	if v := params.Get("unknown"); v != "" {
+		t.Errorf("param 'unknown' is '%s' and should be empty", v)
+	}
+
+	if v := params.Index(1); v != "foo" {
+		t.Errorf("param at index 1 is '%s' and should be 'foo'", v)
+	}
+
+	if v := params.Index(3); v != "" {
+		t.Errorf("param at index 3 is '%s' and should be empty", v)
+	}
+
+	if all := params.All("id"); len(all) != 2 || all[0] != "1" || all[1] != "2" {
+		t.Errorf("all params 'id' are %v and should be [1 2]", all)
+	}
+
+	if m := params.Map(); len(m) != 2 || m["id"] != "2" || m["name"] != "foo" {
+		t.Errorf("param map is %v", m)
+	}
+}
+
+func TestOrderedParams(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	paramHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var entries []string
+
+			for _, e := range OrderedParams(r.Context()) {
+				entries = append(entries, e.Key+"="+e.Value)
+			}
+
+			w.Write([]byte(strings.Join(entries, ",")))
+		})
+	}
+
+	subRouter.Get("/items/:id/*path", paramHandler())
+	router.Route("/groups/:id/:name", subRouter)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/groups/1/foo/items/2/a/b", nil))
+
+	if body, expected := w.Body.String(), "id=1,name=foo,id=2,path=/a/b"; body != expected {
+		t.Errorf("ordered params are '%s' and should be '%s'", body, expected)
+	}
+}
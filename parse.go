@@ -2,6 +2,22 @@ package lionrouter
 
 import (
 	"strings"
+	"unicode"
+)
+
+// PatternSyntax
+// int defines the notation of route patterns
+type PatternSyntax int
+
+const (
+	// SyntaxLion uses :key for named keys and *path for named paths (default)
+	SyntaxLion PatternSyntax = iota
+
+	// SyntaxServeMux uses the go 1.22 http.ServeMux notation {key}, {path...} and {$}
+	SyntaxServeMux
+
+	// SyntaxAuto detects the notation for each pattern, patterns containing braces use SyntaxServeMux
+	SyntaxAuto
 )
 
 type pathKey struct {
@@ -56,6 +72,11 @@ func parsePath(path string) []*pathKey {
 func parseKey(key string) *pathKey {
 	parsed := &pathKey{key, false, false}
 
+	// anonymous path, matches the whole path without capturing
+	if key == "*" {
+		return &pathKey{"", false, true}
+	}
+
 	// return immediately, trivial key found
 	if key == "" || len(key) == 1 {
 		return parsed
@@ -74,3 +95,127 @@ func parseKey(key string) *pathKey {
 
 	return parsed
 }
+
+// String returns the key in pattern notation
+func (k *pathKey) String() string {
+	if k.wildcard {
+		return ":" + k.name
+	}
+
+	if k.wildcardPath {
+		return "*" + k.name
+	}
+
+	return k.name
+}
+
+// validateKeys checks the parsed keys of a route pattern
+// returns error on duplicate param names or if a named path is not the last key
+func validateKeys(keys []*pathKey) error {
+	names := make(map[string]bool)
+
+	for i, k := range keys {
+		if !k.wildcard && !k.wildcardPath {
+			continue
+		}
+
+		if k.wildcardPath && i != len(keys)-1 {
+			return ErrNamedPath
+		}
+
+		if names[k.name] {
+			return ErrDuplicateParam
+		}
+
+		names[k.name] = true
+	}
+
+	return nil
+}
+
+// splitMethod splits an optional method prefix from a pattern, e.g. "GET /users/{id}"
+// returns the method or an empty string and the path
+func splitMethod(pattern string) (string, string) {
+	i := strings.IndexAny(pattern, " \t")
+
+	if i < 0 {
+		return "", pattern
+	}
+
+	return pattern[:i], strings.TrimLeft(pattern[i:], " \t")
+}
+
+// parseMuxPath converts a path in http.ServeMux notation to the router notation
+// A trailing slash matches the whole subtree like an anonymous named path, {$} matches the trailing slash only
+// returns the converted path or ErrPattern if the path cannot be converted, e.g. it contains a host
+func parseMuxPath(path string) (string, error) {
+	if !strings.HasPrefix(path, "/") {
+		return "", ErrPattern
+	}
+
+	split := strings.Split(path[1:], "/")
+	keys := make([]string, len(split))
+
+	for i, key := range split {
+		last := i == len(split)-1
+
+		switch {
+		case key == "{$}" && last:
+			// exact match, trailing slash is ignored by the router
+			keys[i] = ""
+		case key == "" && last:
+			// subtree match
+			keys[i] = "*"
+		case strings.HasPrefix(key, "{") && strings.HasSuffix(key, "}"):
+			name := key[1 : len(key)-1]
+
+			if strings.HasSuffix(name, "...") {
+				// named path only possible at the end
+				if !last {
+					return "", ErrPattern
+				}
+
+				name = strings.TrimSuffix(name, "...")
+				keys[i] = "*" + name
+			} else {
+				keys[i] = ":" + name
+			}
+
+			if !isIdentifier(name) {
+				return "", ErrPattern
+			}
+		case strings.ContainsAny(key, "{}") || strings.HasPrefix(key, ":") || strings.HasPrefix(key, "*"):
+			// braces only allowed around a whole key, literal keys can't start with : or *
+			return "", ErrPattern
+		default:
+			keys[i] = key
+		}
+	}
+
+	return "/" + strings.Join(keys, "/"), nil
+}
+
+// isIdentifier checks whether the name is a valid go identifier, as required for http.ServeMux wildcards
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, c := range name {
+		if !unicode.IsLetter(c) && c != '_' && (i == 0 || !unicode.IsDigit(c)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// translatePath converts the path to the router notation according to the syntax
+// returns the converted path or ErrPattern
+func translatePath(syntax PatternSyntax, path string) (string, error) {
+	if syntax == SyntaxServeMux || (syntax == SyntaxAuto && strings.ContainsAny(path, "{}")) {
+		return parseMuxPath(path)
+	}
+
+	return path, nil
+}
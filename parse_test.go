@@ -74,3 +74,50 @@ func TestParse(t *testing.T) {
 		}
 	}
 }
+
+func TestParseMuxPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/", "/*"},
+		{"/{$}", "/"},
+		{"/users/{id}", "/users/:id"},
+		{"/users/{id}/", "/users/:id/*"},
+		{"/users/{id}/{$}", "/users/:id/"},
+		{"/files/{user_id}/{path...}", "/files/:user_id/*path"},
+	}
+
+	for _, test := range tests {
+		path, err := parseMuxPath(test.path)
+
+		if err != nil {
+			t.Errorf("parsing '%s' failed --> %s", test.path, err.Error())
+		} else if path != test.expected {
+			t.Errorf("parsed path of '%s' is '%s' and should be '%s'", test.path, path, test.expected)
+		}
+	}
+
+	errPaths := []string{
+		"example.com/users",
+		"/users/{}",
+		"/users/{id",
+		"/users/a{id}",
+		"/files/{path...}/x",
+		"/users/{$}/x",
+		"/users/{1d}",
+		"/users/:id",
+	}
+
+	for _, p := range errPaths {
+		if _, err := parseMuxPath(p); err != ErrPattern {
+			t.Errorf("parsing '%s' should fail, error is: %v", p, err)
+		}
+	}
+
+	parsed := parsePath("/static/*")
+
+	if k := parsed[1]; k.name != "" || !k.wildcardPath {
+		t.Errorf("Parsing error, name: %s, wildcard: %t, wildcardPath: %t", k.name, k.wildcard, k.wildcardPath)
+	}
+}
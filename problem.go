@@ -0,0 +1,168 @@
+package lionrouter
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Problem
+// struct is a RFC 9457 problem details object
+type Problem struct {
+	// Type is a URI reference identifying the problem type, about:blank if empty
+	Type string `json:"type"`
+
+	// Title is a short summary of the problem type, the status text if empty
+	Title string `json:"title"`
+
+	// Status code of the response
+	Status int `json:"status"`
+
+	// Detail explains this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+
+	// Instance is a URI reference identifying this occurrence of the problem, the request path if empty
+	Instance string `json:"instance,omitempty"`
+
+	// Extensions holds additional members of the problem, members named like the standard members are ignored
+	Extensions map[string]any `json:"-"`
+}
+
+// MarshalJSON encodes the problem with its extension members
+func (p Problem) MarshalJSON() ([]byte, error) {
+	// alias type without methods to use the default encoding
+	type problem Problem
+
+	b, err := json.Marshal(problem(p))
+
+	if err != nil || len(p.Extensions) == 0 {
+		return b, err
+	}
+
+	members := make(map[string]any, len(p.Extensions)+5)
+
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+
+	// standard members take precedence over extensions
+	if err := json.Unmarshal(b, &members); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(members)
+}
+
+const (
+	// problem details content types
+	contentTypeProblem = "application/problem+json"
+	contentTypeHTML    = "text/html"
+)
+
+// WriteProblem writes the problem as response to the given request
+// The response is negotiated through the Accept header, browsers get a HTML page and other clients application/problem+json
+func WriteProblem(w http.ResponseWriter, r *http.Request, p Problem) {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Add("Vary", "Accept")
+
+	if negotiate(r.Header.Get("Accept"), contentTypeProblem, contentTypeHTML) == contentTypeHTML {
+		h.Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(p.Status)
+
+		title := html.EscapeString(fmt.Sprintf("%d %s", p.Status, p.Title))
+		fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head><title>%s</title></head>\n<body>\n<h1>%s</h1>\n<p>%s</p>\n</body>\n</html>\n", title, title, html.EscapeString(p.Detail))
+
+		return
+	}
+
+	h.Set("Content-Type", contentTypeProblem)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// problemDetails reports whether the router or its parent routers respond with problem details
+func (r *Router) problemDetails(scope *mountScope) bool {
+	return r.ProblemDetails || (scope != nil && scope.problemDetails)
+}
+
+// negotiate picks the offered content type accepted with the highest quality by the given Accept header,
+// the first offer is picked on ties and if no Accept header is sent
+// returns the picked content type or an empty string if no offer is accepted
+func negotiate(accept string, offers ...string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	best, bestQ := "", 0.0
+
+	for _, offer := range offers {
+		if q := acceptQuality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// acceptQuality retrieves the quality of the most specific media range in the Accept header matching the content type
+// application/problem+json is matched by application/json as well
+func acceptQuality(accept string, contentType string) float64 {
+	q, specificity := 0.0, -1
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+
+		s := mediaSpecificity(mediaRange, contentType)
+
+		if s <= specificity {
+			continue
+		}
+
+		specificity, q = s, 1
+
+		for _, param := range params[1:] {
+			if k, v, ok := strings.Cut(strings.TrimSpace(param), "="); ok && strings.TrimSpace(k) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+	}
+
+	return q
+}
+
+// mediaSpecificity reports how specific the media range matches the content type
+// returns 3 for an exact match, 2 for an alias, 1 for a type wildcard, 0 for */* and -1 if not matching
+func mediaSpecificity(mediaRange string, contentType string) int {
+	switch {
+	case mediaRange == contentType:
+		return 3
+	case contentType == contentTypeProblem && mediaRange == "application/json":
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+
+	return -1
+}
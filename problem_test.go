@@ -0,0 +1,147 @@
+package lionrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", contentTypeProblem},
+		{"*/*", contentTypeProblem},
+		{"application/json", contentTypeProblem},
+		{"application/problem+json", contentTypeProblem},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", contentTypeHTML},
+		{"text/*", contentTypeHTML},
+		{"application/json;q=0.5, text/html;q=0.9", contentTypeHTML},
+		{"text/html;q=0.5, */*", contentTypeProblem},
+		{"image/png", ""},
+	}
+
+	for _, test := range tests {
+		if offer := negotiate(test.accept, contentTypeProblem, contentTypeHTML); offer != test.expected {
+			t.Errorf("accept '%s' negotiated '%s' and should be '%s'", test.accept, offer, test.expected)
+		}
+	}
+}
+
+func TestProblemDetails(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	router.ProblemDetails = true
+	router.HandleMethodNotAllowed = true
+
+	router.Post("/users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	router.Get("/panic", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("PANIC")
+	}))
+	subRouter.Get("/users/:id", HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return NewHTTPError(http.StatusNotFound, errors.New("user <42> not found"))
+	}))
+	router.Route("/sub", subRouter)
+
+	tests := []struct {
+		method   string
+		path     string
+		expected Problem
+	}{
+		{http.MethodGet, "/unknown", Problem{"about:blank", "Not Found", http.StatusNotFound, "No route matches GET /unknown", "/unknown", nil}},
+		{http.MethodGet, "/users", Problem{"about:blank", "Method Not Allowed", http.StatusMethodNotAllowed, "Method GET is not allowed for /users, allowed: POST", "/users", nil}},
+		{http.MethodGet, "/panic", Problem{"about:blank", "Internal Server Error", http.StatusInternalServerError, "", "/panic", nil}},
+		{http.MethodGet, "/sub/unknown", Problem{"about:blank", "Not Found", http.StatusNotFound, "No route matches GET /sub/unknown", "/sub/unknown", nil}},
+		{http.MethodGet, "/sub/users/42", Problem{"about:blank", "Not Found", http.StatusNotFound, "user <42> not found", "/sub/users/42", nil}},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(test.method, test.path, nil)
+		req.Header.Set("Accept", "application/json")
+		router.ServeHTTP(w, req)
+
+		if w.Code != test.expected.Status {
+			t.Errorf("route '%s' should be %d, router responded with: %d", test.path, test.expected.Status, w.Code)
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != contentTypeProblem {
+			t.Errorf("route '%s' content type is '%s' and should be '%s'", test.path, ct, contentTypeProblem)
+		}
+
+		var problem Problem
+
+		if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("route '%s' responded with invalid json: %v", test.path, err)
+		}
+
+		if !reflect.DeepEqual(problem, test.expected) {
+			t.Errorf("route '%s' responded with %+v and should be %+v", test.path, problem, test.expected)
+		}
+
+		// browsers get a HTML page
+		w = httptest.NewRecorder()
+		req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+		router.ServeHTTP(w, req)
+
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, contentTypeHTML) {
+			t.Errorf("route '%s' content type is '%s' and should be '%s'", test.path, ct, contentTypeHTML)
+		}
+
+		if body := w.Body.String(); strings.Contains(body, "<42>") || !strings.Contains(body, test.expected.Title) {
+			t.Errorf("route '%s' responded with invalid html: %s", test.path, body)
+		}
+	}
+}
+
+func TestMountedProblemDetails(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	subRouter.ProblemDetails = true
+	subRouter.HandleMethodNotAllowed = true
+
+	subRouter.Post("/users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	router.Route("/sub", subRouter)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/sub/unknown", http.StatusNotFound},
+		{"/sub/users", http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if w.Code != test.status {
+			t.Errorf("route '%s' should be %d, router responded with: %d", test.path, test.status, w.Code)
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != contentTypeProblem {
+			t.Errorf("route '%s' content type is '%s' and should be '%s'", test.path, ct, contentTypeProblem)
+		}
+	}
+
+	// the parent router keeps its plain text responses
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	if body := w.Body.String(); body != "404 - Page not found!" {
+		t.Errorf("route '/unknown' responded with '%s'", body)
+	}
+}
@@ -0,0 +1,54 @@
+package lionrouter
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicHandler
+// func handles a panic recovered while serving a request, recovered holds the value passed to panic
+type PanicHandler func(w http.ResponseWriter, r *http.Request, recovered any)
+
+// handlePanic handles a panic recovered while serving the given request
+// The panic and stack are logged through the request logger, the response is aborted if it has already been started
+func (r *Router) handlePanic(w *responseWriter, req *http.Request, recovered any, scope *mountScope) {
+	// abort handler panics abort the response silently, pass them through to net/http
+	if recovered == http.ErrAbortHandler {
+		panic(recovered)
+	}
+
+	r.loggerFor(req.Context(), scope).LogAttrs(req.Context(), slog.LevelError, "panic serving request",
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Any("panic", recovered),
+		slog.String("stack", string(debug.Stack())),
+	)
+
+	// a second header cannot be written, abort the response instead
+	if w.started {
+		panic(http.ErrAbortHandler)
+	}
+
+	r.panicHandler(scope)(w.wrapped, req, recovered)
+}
+
+// panicHandler retrieves the panic handler of the router
+func (r *Router) panicHandler(scope *mountScope) PanicHandler {
+	if r.PanicHandler != nil {
+		// use custom panic handler
+		return r.PanicHandler
+	}
+
+	// use default fallback handler for 500 response
+	if r.problemDetails(scope) {
+		return func(w http.ResponseWriter, r *http.Request, recovered any) {
+			WriteProblem(w, r, Problem{Status: http.StatusInternalServerError})
+		}
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, recovered any) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Internal server error!"))
+	}
+}
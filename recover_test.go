@@ -0,0 +1,78 @@
+package lionrouter
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	var output bytes.Buffer
+	router.Logger = slog.New(slog.NewTextHandler(&output, nil))
+
+	panicHandler := func(started bool) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if started {
+				w.Write([]byte("PARTIAL"))
+			}
+
+			panic("PANIC")
+		})
+	}
+
+	router.Get("/panic", panicHandler(false))
+	router.Get("/started", panicHandler(true))
+	router.Get("/abort", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	subRouter.Get("/panic", panicHandler(false))
+	router.Route("/sub", subRouter)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("route '/panic' should be %d, router responded with: %d", http.StatusInternalServerError, w.Code)
+	}
+
+	if !strings.Contains(output.String(), "panic=PANIC") || !strings.Contains(output.String(), "goroutine") || !strings.Contains(output.String(), "pattern=/panic") {
+		t.Errorf("panic and stack should be logged through the request logger, got: %s", output.String())
+	}
+
+	// natively mounted sub-routers are recovered by their own panic handler
+	subRouter.PanicHandler = func(w http.ResponseWriter, r *http.Request, recovered any) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(recovered.(string) + " " + Pattern(r.Context())))
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sub/panic", nil))
+
+	if w.Code != http.StatusServiceUnavailable || w.Body.String() != "PANIC /sub/panic" {
+		t.Errorf("route '/sub/panic' responded with %d '%s'", w.Code, w.Body.String())
+	}
+
+	// started responses and abort panics are aborted
+	for _, path := range []string{"/started", "/abort"} {
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != http.ErrAbortHandler {
+					t.Errorf("route '%s' should abort the response, recovered: %v", path, recovered)
+				}
+			}()
+
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		}()
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("route '/abort' should not be answered, router responded with: %d", w.Code)
+	}
+}
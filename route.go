@@ -0,0 +1,140 @@
+package lionrouter
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Route
+// struct represents an assigned route, used to set further options
+type Route struct {
+	router *Router
+
+	method  string
+	pattern string
+	keys    []*pathKey
+	name    string
+}
+
+// newRoute creates a new route for given method and pattern
+func newRoute(router *Router, method string, pattern string) *Route {
+	return &Route{
+		router:  router,
+		method:  method,
+		pattern: pattern,
+		keys:    parsePath(pattern),
+	}
+}
+
+// Name assigns the name to the route, the name is used for reverse url generation
+// Panics if the name is empty or already in use, a nil route returned by a failed assignment is ignored
+func (rt *Route) Name(name string) *Route {
+	if rt == nil {
+		return rt
+	}
+
+	err := rt.router.collect(rt.router.nameRoute(name, rt))
+
+	if err != nil && !rt.router.CollectErrors {
+		panic(err)
+	}
+
+	return rt
+}
+
+// nameRoute assigns the name to the given route
+// returns error if name is empty or already in use
+func (r *Router) nameRoute(name string, route *Route) error {
+	if r.trie.frozen {
+		return &RouteError{route.method, route.pattern, "", ErrFrozen}
+	}
+
+	if name == "" || route.name != "" || r.named[name] != nil {
+		return &RouteError{route.method, route.pattern, "", ErrRouteName}
+	}
+
+	if r.named == nil {
+		r.named = make(map[string]*Route)
+	}
+
+	// name is set on the leaf to be retrieved while serving
+	if n := r.trie.find(route.keys); n != nil && n.leaf != nil {
+		n.leaf.setName(route.method, name)
+	}
+
+	route.name = name
+	r.named[name] = route
+
+	return nil
+}
+
+// URL builds the escaped path of the named route
+// params are passed as key value pairs, e.g. URL("user.file", "id", "42", "path", "a/b.txt")
+// returns the path or error if the route is unknown or params are missing or unknown
+func (r *Router) URL(name string, params ...string) (string, error) {
+	route := r.named[name]
+
+	if route == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownRoute, name)
+	}
+
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("%w: odd count of key value pairs", ErrURLParams)
+	}
+
+	values := make(map[string]string, len(params)/2)
+
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	segments := make([]string, len(route.keys))
+
+	for i, key := range route.keys {
+		// static keys are used as is
+		if !key.wildcard && !key.wildcardPath {
+			segments[i] = url.PathEscape(key.name)
+			continue
+		}
+
+		// anonymous path is omitted
+		if key.wildcardPath && key.name == "" {
+			segments = segments[:i]
+			break
+		}
+
+		value, ok := values[key.name]
+
+		if !ok {
+			return "", fmt.Errorf("%w: missing param '%s'", ErrURLParams, key.name)
+		}
+
+		delete(values, key.name)
+
+		if key.wildcard {
+			// an empty value would not match the key
+			if value == "" {
+				return "", fmt.Errorf("%w: empty param '%s'", ErrURLParams, key.name)
+			}
+
+			segments[i] = url.PathEscape(value)
+			continue
+		}
+
+		// escape each key of a named path
+		split := strings.Split(strings.TrimPrefix(value, "/"), "/")
+
+		for j, s := range split {
+			split[j] = url.PathEscape(s)
+		}
+
+		segments[i] = strings.Join(split, "/")
+	}
+
+	for key := range values {
+		return "", fmt.Errorf("%w: unknown param '%s'", ErrURLParams, key)
+	}
+
+	return "/" + strings.Join(segments, "/"), nil
+}
@@ -0,0 +1,67 @@
+package lionrouter
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRouteURL(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// do nothing
+		})
+	}
+
+	router := New()
+	router.Get("/", testHandler()).Name("index")
+	router.Get("/users/:id/files/*path", testHandler()).Name("user.file")
+	router.Post("/hello world/:name", testHandler()).Name("hello")
+
+	tests := []struct {
+		name     string
+		params   []string
+		expected string
+	}{
+		{"index", nil, "/"},
+		{"user.file", []string{"id", "42", "path", "a/b.txt"}, "/users/42/files/a/b.txt"},
+		{"user.file", []string{"path", "/a b/c?.txt", "id", "4/2"}, "/users/4%2F2/files/a%20b/c%3F.txt"},
+		{"hello", []string{"name", "peter"}, "/hello%20world/peter"},
+	}
+
+	for _, test := range tests {
+		path, err := router.URL(test.name, test.params...)
+
+		if err != nil {
+			t.Errorf("url for route '%s' failed --> %s", test.name, err.Error())
+		} else if path != test.expected {
+			t.Errorf("url for route '%s' is '%s' and should be '%s'", test.name, path, test.expected)
+		}
+	}
+
+	errTests := []struct {
+		name   string
+		params []string
+		err    error
+	}{
+		{"unknown", nil, ErrUnknownRoute},
+		{"user.file", []string{"id", "42"}, ErrURLParams},
+		{"user.file", []string{"id", "42", "path", "a", "foo", "bar"}, ErrURLParams},
+		{"user.file", []string{"id"}, ErrURLParams},
+		{"user.file", []string{"id", "", "path", "a"}, ErrURLParams},
+	}
+
+	for _, test := range errTests {
+		if _, err := router.URL(test.name, test.params...); !errors.Is(err, test.err) {
+			t.Errorf("url for route '%s' with params %v should fail with '%v', error is: %v", test.name, test.params, test.err, err)
+		}
+	}
+
+	var routeErr *RouteError
+
+	if err := router.nameRoute("index", newRoute(router, http.MethodGet, "/foo")); !errors.As(err, &routeErr) || !errors.Is(err, ErrRouteName) {
+		t.Errorf("duplicate route name should fail with route error, error is: %v", err)
+	} else if routeErr.Method != http.MethodGet || routeErr.Pattern != "/foo" {
+		t.Errorf("route error should hold the route, error is: %v", err)
+	}
+}
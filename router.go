@@ -2,12 +2,11 @@ package lionrouter
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
-)
-
-const (
-	// context iota keys
-	contextParams = iota // RouterParams context key
+	"strings"
 )
 
 // RouterParam
@@ -20,11 +19,62 @@ type Router struct {
 	// trie holds the routes, a trie is a special string search tree
 	trie *trie
 
-	// middleware stack
-	middleware []func(http.Handler) http.Handler
+	// named routes used for reverse url generation
+	named map[string]*Route
+
+	// errors occurred while assigning routes and middleware
+	errs []error
+
+	// CollectErrors records assignment errors for Validate instead of panicking
+	CollectErrors bool
+
+	// middleware stack in registration order
+	middleware []middlewareEntry
+
+	// MiddlewareOrder defines the execution order of the middleware stack
+	MiddlewareOrder MiddlewareOrder
 
-	// 404 and 500 handler which called if no handler for route assigned or error occurs
+	// Syntax defines the notation of route patterns
+	Syntax PatternSyntax
+
+	// 404 handler which called if no handler for route assigned
 	NotFoundHandler http.Handler
+
+	// HandleMethodNotAllowed enables 405 responses if a route exists but no handler for the method is assigned
+	HandleMethodNotAllowed bool
+
+	// 405 handler which called if enabled by HandleMethodNotAllowed
+	MethodNotAllowedHandler http.Handler
+
+	// PanicHandler handles panics recovered while serving a request, the default responds with 500
+	// Natively mounted sub-routers without own PanicHandler are recovered by the parent router
+	PanicHandler PanicHandler
+
+	// ProblemDetails enables RFC 9457 problem details for the responses generated by the router,
+	// sub-routers inherit the option from their parent router
+	ProblemDetails bool
+
+	// SuggestRoutes enables suggestions of registered routes close to paths not found,
+	// sub-routers inherit the option from their parent router
+	SuggestRoutes bool
+
+	// Tracer starts spans around the dispatch of requests and each middleware, sub-routers inherit the tracer
+	Tracer Tracer
+
+	// Logger is the base of the request loggers retrieved through Logger(ctx), slog.Default() if not set,
+	// sub-routers without own Logger use the one of their parent router
+	Logger *slog.Logger
+
+	// RequestIDHeader is the request header holding the request id logged by the request loggers,
+	// DefaultRequestIDHeader if not set, sub-routers without own header use the one of their parent router
+	// Set AccessLogOptions.RequestIDHeader to the same header to log the same id in the access log
+	RequestIDHeader string
+
+	// Metrics observes the requests of the router and its sub-routers, assign it to the outermost router only
+	Metrics *Metrics
+
+	// ErrorHandler handles errors returned by HandlerFunc handlers, sub-routers without own ErrorHandler use the one of their parent router
+	ErrorHandler ErrorHandler
 }
 
 // New creates a new router instance
@@ -36,99 +86,267 @@ func New() *Router {
 }
 
 // Get assigns the handler as HTTP-GET Route for given path
-func (r *Router) Get(path string, handler http.Handler) {
-	err := r.handle(http.MethodGet, path, handler)
+// returns the route to set further options
+func (r *Router) Get(path string, handler http.Handler) *Route {
+	route, err := r.Handle(http.MethodGet, path, handler)
 
-	if err != nil {
+	if err != nil && !r.CollectErrors {
 		panic(err)
 	}
+
+	return route
 }
 
 // Post assigns the handler as HTTP-POST Route for given path
-func (r *Router) Post(path string, handler http.Handler) {
-	err := r.handle(http.MethodPost, path, handler)
+// returns the route to set further options
+func (r *Router) Post(path string, handler http.Handler) *Route {
+	route, err := r.Handle(http.MethodPost, path, handler)
 
-	if err != nil {
+	if err != nil && !r.CollectErrors {
 		panic(err)
 	}
+
+	return route
 }
 
 // Put assigns the handler as HTTP-PUT Route for given path
-func (r *Router) Put(path string, handler http.Handler) {
-	err := r.handle(http.MethodPut, path, handler)
+// returns the route to set further options
+func (r *Router) Put(path string, handler http.Handler) *Route {
+	route, err := r.Handle(http.MethodPut, path, handler)
 
-	if err != nil {
+	if err != nil && !r.CollectErrors {
 		panic(err)
 	}
+
+	return route
 }
 
 // Patch assigns the handler as HTTP-PATCH Route for given path
-func (r *Router) Patch(path string, handler http.Handler) {
-	err := r.handle(http.MethodPatch, path, handler)
+// returns the route to set further options
+func (r *Router) Patch(path string, handler http.Handler) *Route {
+	route, err := r.Handle(http.MethodPatch, path, handler)
 
-	if err != nil {
+	if err != nil && !r.CollectErrors {
 		panic(err)
 	}
+
+	return route
 }
 
 // Delete assigns the handler as HTTP-DELETE Route for given path
-func (r *Router) Delete(path string, handler http.Handler) {
-	err := r.handle(http.MethodDelete, path, handler)
+// returns the route to set further options
+func (r *Router) Delete(path string, handler http.Handler) *Route {
+	route, err := r.Handle(http.MethodDelete, path, handler)
 
-	if err != nil {
+	if err != nil && !r.CollectErrors {
 		panic(err)
 	}
+
+	return route
 }
 
 // Head assigns the handler as HTTP-HEAD Route for given path
-func (r *Router) Head(path string, handler http.Handler) {
-	err := r.handle(http.MethodHead, path, handler)
+// returns the route to set further options
+func (r *Router) Head(path string, handler http.Handler) *Route {
+	route, err := r.Handle(http.MethodHead, path, handler)
 
-	if err != nil {
+	if err != nil && !r.CollectErrors {
 		panic(err)
 	}
+
+	return route
 }
 
 // Options assigns the handler as HTTP-OPTIONS Route for given path
-func (r *Router) Options(path string, handler http.Handler) {
-	err := r.handle(http.MethodOptions, path, handler)
+// returns the route to set further options
+func (r *Router) Options(path string, handler http.Handler) *Route {
+	route, err := r.Handle(http.MethodOptions, path, handler)
 
-	if err != nil {
+	if err != nil && !r.CollectErrors {
 		panic(err)
 	}
+
+	return route
+}
+
+// Handle assigns the handler for given method and route
+// returns the route or error if assignment fails
+func (r *Router) Handle(method string, path string, handler http.Handler) (*Route, error) {
+	pattern, err := r.translate(method, path)
+
+	if err == nil {
+		err = r.trie.addHandler(method, pattern, handler)
+	}
+
+	if err != nil {
+		return nil, r.collect(err)
+	}
+
+	return newRoute(r, method, pattern), nil
 }
 
-// handler assigns the handler for given method and route
+// Register assigns the handler for a pattern with optional method prefix like the go http.ServeMux,
+// e.g. "GET /users/{id}", the handler is assigned for all methods if no method is given
+// The catch-all pattern "/" without method is assigned as 404 handler of the router root
+// Unlike http.ServeMux named keys and paths are matched first, patterns sharing a position with static keys
+// of assigned routes fail with ErrShadowed
 // returns error if assignment fails
-func (r *Router) handle(method string, path string, handler http.Handler) error {
-	return r.trie.addHandler(method, path, handler)
+func (r *Router) Register(pattern string, handler http.Handler) error {
+	method, path := splitMethod(pattern)
+	pattern, err := r.translate(method, path)
+
+	if err != nil {
+		return r.collect(err)
+	}
+
+	// the catch-all would shadow any other route
+	if method == "" && pattern == "/*" {
+		return r.addFallback(path, handler, false)
+	}
+
+	methods := leafMethods[:]
+
+	if method != "" {
+		methods = []string{method}
+	}
+
+	// check all methods first, the handler is assigned for all methods or none
+	for _, m := range methods {
+		if err := r.trie.checkHandler(m, pattern, handler); err != nil {
+			return r.collect(err)
+		}
+	}
+
+	if err := r.trie.shadowed(method, pattern, parsePath(pattern)); err != nil {
+		return r.collect(err)
+	}
+
+	for _, m := range methods {
+		if _, err := r.Handle(m, path, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// translate converts the path to the router notation according to the router syntax
+// returns the converted path or a *RouteError
+func (r *Router) translate(method string, path string) (string, error) {
+	pattern, err := translatePath(r.Syntax, path)
+
+	if err != nil {
+		return "", &RouteError{method, path, "", err}
+	}
+
+	return pattern, nil
 }
 
 // Route assigns the given handler for given path, if route is called the request is passed through this handler
-// Used for sub-routing, path will be stripped through http.StripPrefix middleware before request passed to handler
+// Used for sub-routing, the matched path prefix will be stripped before request passed to handler
+// The path may contain named keys, their values are passed through the request context
 func (r *Router) Route(path string, handler http.Handler) {
-	err := r.trie.addRouter(path, http.StripPrefix(path, handler))
+	err := r.Mount(path, handler)
 
-	if err != nil {
+	if err != nil && !r.CollectErrors {
 		panic(err)
 	}
 }
 
-// Use assigns a middleware stack to the whole router instance
-func (r *Router) Use(middleware ...func(http.Handler) http.Handler) {
-	for _, m := range middleware {
-		r.middleware = append(r.middleware, m)
+// Mount assigns the given handler for given path like Route
+// returns error if assignment fails
+func (r *Router) Mount(path string, handler http.Handler) error {
+	pattern, err := r.translate("", path)
+
+	// sub-routers match the whole subtree anyway
+	if err == nil {
+		err = r.trie.addRouter(strings.TrimSuffix(pattern, "/*"), handler)
+	}
+
+	return r.collect(err)
+}
+
+// collect records the given error for Validate if CollectErrors is set,
+// otherwise the error is panicked or returned to the caller only
+// returns the error
+func (r *Router) collect(err error) error {
+	if err != nil && r.CollectErrors {
+		r.errs = append(r.errs, err)
+	}
+
+	return err
+}
+
+// Validate reports all errors collected while assigning routes and middleware with CollectErrors set,
+// errors of mounted lionrouter sub-routers are included
+// returns the joined errors or nil
+func (r *Router) Validate() error {
+	errs := append([]error{}, r.errs...)
+
+	r.trie.walk(func(pattern string, n *node) error {
+		if child, ok := n.router.(*Router); ok {
+			if err := child.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return nil
+	})
+
+	return errors.Join(errs...)
+}
+
+// NotFound assigns the 404 handler for all requests below the given path prefix,
+// the handler of the deepest prefix visited by the request is used
+func (r *Router) NotFound(path string, handler http.Handler) {
+	err := r.addFallback(path, handler, false)
+
+	if err != nil && !r.CollectErrors {
+		panic(err)
 	}
 }
 
-// middlewareChain builds the middleware stack
-// returns the http.Handler stack
-func (r *Router) middlewareChain(next http.Handler) http.Handler {
-	for _, m := range r.middleware {
-		next = m(next)
+// MethodNotAllowed assigns the 405 handler for all requests below the given path prefix,
+// the handler of the deepest prefix visited by the request is used
+// 405 responses are enabled for the prefix even if HandleMethodNotAllowed is not set
+func (r *Router) MethodNotAllowed(path string, handler http.Handler) {
+	err := r.addFallback(path, handler, true)
+
+	if err != nil && !r.CollectErrors {
+		panic(err)
+	}
+}
+
+// addFallback assigns the 404 or 405 handler for given path prefix
+// returns error if assignment fails
+func (r *Router) addFallback(path string, handler http.Handler, methodNotAllowed bool) error {
+	pattern, err := r.translate("", path)
+
+	// the prefix matches the whole subtree anyway
+	if err == nil {
+		err = r.trie.addFallback(strings.TrimSuffix(pattern, "/*"), handler, methodNotAllowed)
 	}
 
-	return next
+	return r.collect(err)
+}
+
+// Use assigns a middleware stack to the whole router instance
+// The execution order of the stack is defined by MiddlewareOrder
+func (r *Router) Use(middleware ...func(http.Handler) http.Handler) {
+	// check if router is read-only
+	if r.trie.frozen {
+		err := r.collect(ErrFrozen)
+
+		if !r.CollectErrors {
+			panic(err)
+		}
+
+		return
+	}
+
+	for _, m := range middleware {
+		r.middleware = append(r.middleware, middlewareEntry{fn: m})
+	}
 }
 
 // ServeHTTP handles a given request
@@ -136,57 +354,279 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// get path of current request
 	path := req.URL.Path
 
+	r.serve(w, req, path, splitPath(path), nil)
+}
+
+// serve handles a given request using the given path and its split keys
+// path is the part of the request path left to the router, scope is set if natively mounted as sub-router
+func (r *Router) serve(w http.ResponseWriter, req *http.Request, path string, parsed []string, scope *mountScope) {
 	// get current context
 	ctx := req.Context()
 
+	// request served without panic
+	served := false
+
+	// observe the request for metrics, the responses of panics recovered below are observed as well
+	if r.Metrics != nil {
+		var observed func(served bool)
+		w, ctx, observed = r.observeMetrics(w, ctx, req.Method)
+
+		defer func() {
+			observed(served)
+		}()
+	}
+
+	// recover panics of the handlers
+	if scope == nil || r.PanicHandler != nil {
+		rw := newResponseWriter(w)
+		w = rw.wrapped
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				r.handlePanic(rw, req, recovered, scope)
+			}
+		}()
+	}
+
+	// route capture of the dispatch span, the span is started by the outermost router tracing the request
+	tracer := r.tracer(scope)
+	var capture *routeCapture
+
+	if tracer != nil && (scope == nil || scope.tracer == nil) {
+		ctx, capture = withRouteCapture(ctx)
+	}
+
 	// get handler from trie
-	handler, params := r.trie.get(req.Method, path)
+	m := r.trie.get(req.Method, parsed)
+	handler, params, depth := m.handler, m.params, m.depth
 
 	if handler != nil {
-		// check if params are given and add them to the current context
-		if params != nil {
-			ctx = context.WithValue(ctx, contextParams, params)
+		// create route context, params of parent routers are merged if mounted as sub-router
+		rc := newRouteContext(ctx, m.node, req.Method, params, m.list)
+		rc.problemDetails = r.problemDetails(scope)
+
+		if r.ErrorHandler != nil {
+			rc.errorHandler = r.ErrorHandler
 		}
 
+		// request logger, built once retrieved through Logger(ctx)
+		r.setRequestLogger(&rc.log, req, scope)
+
+		// pass the remaining path to sub-routers
+		if depth > 0 {
+			prefix := pathPrefix(path, depth)
+
+			rc.MountPath += prefix
+			rc.mount = true
+
+			if child, ok := handler.(*Router); ok {
+				handler = r.mountRouter(child, path[len(prefix):], parsed[depth:], &m, scope)
+			} else {
+				// strip everything matched so far, the request path might be the original one
+				consumed := len(req.URL.Path) - len(path) + len(prefix)
+				handler = stripMount(req.URL.Path[:consumed], handler)
+			}
+		}
+
+		// add route context to the current context
+		ctx = context.WithValue(ctx, contextRouteKey, rc)
+		captureRoute(ctx, rc, "")
+
 		// get middleware chain
-		handler = r.middlewareChain(handler)
-	} else if r.NotFoundHandler == nil {
-		// use default fallback handler for 404 response
-		handler = func() http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				w.WriteHeader(http.StatusNotFound)
-				w.Write([]byte("404 - Page not found!"))
-			})
+		handler = r.middlewareChain(handler, tracer)
+	} else if methods := r.allowedMethods(&m, scope); methods != nil {
+		// route exists, but no handler for method assigned
+		w.Header().Set("Allow", strings.Join(methods, ", "))
+		handler = r.methodNotAllowedHandler(&m, scope)
+		captureRoute(ctx, nil, unmatchedMethodNotAllowed)
+		ctx = r.withUnmatchedLogger(ctx, req, scope, unmatchedMethodNotAllowed)
+	} else {
+		if r.suggestRoutes(scope) {
+			ctx = r.withSuggestions(ctx, w, req, path, parsed, &m)
+		}
+
+		handler = r.notFoundHandler(&m, scope)
+		captureRoute(ctx, nil, unmatchedNotFound)
+		ctx = r.withUnmatchedLogger(ctx, req, scope, unmatchedNotFound)
+	}
+
+	// trace the dispatch, the span is named after the route captured above
+	if capture != nil {
+		var traced func(served bool)
+		w, ctx, traced = traceDispatch(tracer, w, ctx, req, capture)
+
+		defer func() {
+			traced(served)
 		}()
+	}
+
+	// provide params through the request path values as well, requests passed through parent routers
+	// are cloned to keep their path values untouched
+	if _, nested := RouteContextFrom(req.Context()); nested && m.handler != nil && len(m.params) > 0 {
+		req = req.Clone(ctx)
 	} else {
-		// use custom 404 handler
-		handler = r.NotFoundHandler
+		req = req.WithContext(ctx)
+	}
+
+	if m.handler != nil {
+		setPathValues(req, m.params, m.node.key)
 	}
 
 	// pass through the request to the given http.Handler
-	handler.ServeHTTP(w, req.WithContext(ctx))
+	handler.ServeHTTP(w, req)
+	served = true
+}
+
+// allowedMethods retrieves the methods assigned to the matched node if 405 responses are enabled
+// 405 responses are enabled by the router, parent routers or a 405 handler assigned for a matched prefix
+// returns the methods or nil
+func (r *Router) allowedMethods(m *trieMatch, scope *mountScope) []string {
+	if !r.HandleMethodNotAllowed && m.methodNotAllowed == nil && (scope == nil || !scope.handleMethodNotAllowed) {
+		return nil
+	}
+
+	return m.node.methods()
+}
+
+// notFoundHandler retrieves the 404 handler of the deepest matched prefix, the router
+// or the one inherited from parent routers
+func (r *Router) notFoundHandler(m *trieMatch, scope *mountScope) http.Handler {
+	if handler := r.assignedNotFound(m, scope); handler != nil {
+		return handler
+	}
+
+	// use default fallback handler for 404 response
+	if r.problemDetails(scope) {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p := Problem{
+				Status: http.StatusNotFound,
+				Detail: fmt.Sprintf("No route matches %s %s", r.Method, r.URL.Path),
+			}
+
+			if suggestions := Suggestions(r.Context()); suggestions != nil {
+				p.Detail += ", did you mean " + strings.Join(suggestions, " or ") + "?"
+				p.Extensions = map[string]any{"suggestions": suggestions}
+			}
+
+			WriteProblem(w, r, p)
+		})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - Page not found!"))
+	})
+}
+
+// assignedNotFound retrieves the 404 handler assigned for the deepest matched prefix, the router
+// or inherited from parent routers
+// returns the handler or nil if the default handler is used
+func (r *Router) assignedNotFound(m *trieMatch, scope *mountScope) http.Handler {
+	if m.notFound != nil {
+		// use 404 handler assigned for the prefix
+		return m.notFound
+	}
+
+	if r.NotFoundHandler != nil {
+		// use custom 404 handler
+		return r.NotFoundHandler
+	}
+
+	if scope != nil {
+		// use 404 handler of parent router
+		return scope.notFound
+	}
+
+	return nil
+}
+
+// methodNotAllowedHandler retrieves the 405 handler of the deepest matched prefix, the router
+// or the one inherited from parent routers
+func (r *Router) methodNotAllowedHandler(m *trieMatch, scope *mountScope) http.Handler {
+	if handler := r.assignedMethodNotAllowed(m, scope); handler != nil {
+		return handler
+	}
+
+	// use default fallback handler for 405 response
+	if r.problemDetails(scope) {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			WriteProblem(w, r, Problem{
+				Status: http.StatusMethodNotAllowed,
+				Detail: fmt.Sprintf("Method %s is not allowed for %s, allowed: %s", r.Method, r.URL.Path, w.Header().Get("Allow")),
+			})
+		})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		w.Write([]byte("405 - Method not allowed!"))
+	})
+}
+
+// assignedMethodNotAllowed retrieves the 405 handler assigned for the deepest matched prefix, the router
+// or inherited from parent routers
+// returns the handler or nil if the default handler is used
+func (r *Router) assignedMethodNotAllowed(m *trieMatch, scope *mountScope) http.Handler {
+	if m.methodNotAllowed != nil {
+		// use 405 handler assigned for the prefix
+		return m.methodNotAllowed
+	}
+
+	if r.MethodNotAllowedHandler != nil {
+		// use custom 405 handler
+		return r.MethodNotAllowedHandler
+	}
+
+	if scope != nil {
+		// use 405 handler of parent router
+		return scope.methodNotAllowed
+	}
+
+	return nil
 }
 
 // Params extracts the params map from the given context
 // It returns the params string map or nil
 func Params(ctx context.Context) (RouterParam, bool) {
-	// get params from context
-	params, ok := ctx.Value(contextParams).(RouterParam)
+	// get route context from context
+	rc, ok := RouteContextFrom(ctx)
 
 	// check if params exist in given context and return them
-	if ok {
-		return params, true
+	if ok && rc.Params != nil {
+		return rc.Params, true
 	}
 
 	// return error because no params exist
 	return nil, false
 }
 
+// mergeParams adds the parent params to the given params
+// On duplicate names the value captured by the innermost router is kept
+func mergeParams(params RouterParam, parent RouterParam) {
+	for k, v := range parent {
+		if _, ok := params[k]; !ok {
+			params[k] = v
+		}
+	}
+}
+
+// setPathValues sets the params as path values of the request, retrievable through req.PathValue
+// The leading slash of a named path is trimmed to match the values set by http.ServeMux
+func setPathValues(req *http.Request, params RouterParam, key *pathKey) {
+	for k, v := range params {
+		if key.wildcardPath && k == key.name {
+			v = strings.TrimPrefix(v, "/")
+		}
+
+		req.SetPathValue(k, v)
+	}
+}
+
 // Param extracts the single param value from the given context and key
 // It returns the param as string or an empty string
 func Param(ctx context.Context, key string) string {
 	// get params from context
-	params, ok := ctx.Value(contextParams).(RouterParam)
+	params, ok := Params(ctx)
 
 	// check if params exist in given context and return them
 	if ok {
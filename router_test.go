@@ -1,9 +1,11 @@
 package lionrouter
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -273,3 +275,244 @@ func TestSubRouter(t *testing.T) {
 
 	srv.Close()
 }
+
+func TestValidate(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// do nothing
+		})
+	}
+
+	router := New()
+	subRouter := New()
+
+	router.CollectErrors = true
+	subRouter.CollectErrors = true
+
+	if err := router.Validate(); err != nil {
+		t.Errorf("validate should not fail without assignment errors, error is: %v", err)
+	}
+
+	if _, err := router.Handle(http.MethodGet, "/foo", testHandler()); err != nil {
+		t.Errorf("handle failed --> %s", err.Error())
+	}
+
+	if _, err := router.Handle("PROPFIND", "/foo", testHandler()); !errors.Is(err, ErrUnknownHTTPMethod) {
+		t.Errorf("handle with unknown method should fail, error is: %v", err)
+	}
+
+	if err := router.Mount("/foo", testHandler()); !errors.Is(err, ErrAssignment) {
+		t.Errorf("mount on assigned route should fail, error is: %v", err)
+	}
+
+	// collected errors don't panic
+	router.Get("/foo", testHandler()).Name("foo")
+	router.UseAfter("unknown", "foo", func(next http.Handler) http.Handler { return next })
+	subRouter.Get("/bar", nil)
+	router.Route("/sub", subRouter)
+
+	err := router.Validate()
+
+	for _, expected := range []error{ErrUnknownHTTPMethod, ErrAssignment, ErrAlreadyAssigned, ErrMiddlewareNotFound, ErrNilHandler} {
+		if !errors.Is(err, expected) {
+			t.Errorf("validate should report '%v', error is: %v", expected, err)
+		}
+	}
+	// errors returned to the caller are not recorded without CollectErrors
+	router = New()
+	router.Get("/foo", testHandler())
+
+	for i := 0; i < 3; i++ {
+		if _, err := router.Handle(http.MethodGet, "/foo", testHandler()); !errors.Is(err, ErrAlreadyAssigned) {
+			t.Errorf("duplicate route should fail, error is: %v", err)
+		}
+	}
+
+	if err := router.Validate(); err != nil {
+		t.Errorf("validate should not report handled errors, error is: %v", err)
+	}
+
+	if err := router.Freeze(); err != nil {
+		t.Errorf("freeze should not report handled errors, error is: %v", err)
+	}
+}
+
+func TestSyntax(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.PathValue("id") + r.PathValue("path")))
+		})
+	}
+
+	router := New()
+	router.Syntax = SyntaxAuto
+
+	router.Get("/users/{id}", testHandler())
+	router.Get("/legacy/:id", testHandler())
+	router.Register("POST /files/{path...}", testHandler())
+	router.Register("/exact/{$}", testHandler())
+
+	tests := []struct {
+		method   string
+		path     string
+		status   int
+		expected string
+	}{
+		{http.MethodGet, "/users/42", http.StatusOK, "42"},
+		{http.MethodGet, "/legacy/42", http.StatusOK, "42"},
+		{http.MethodPost, "/files/a/b.txt", http.StatusOK, "a/b.txt"},
+		{http.MethodGet, "/files/a/b.txt", http.StatusNotFound, "404 - Page not found!"},
+		{http.MethodGet, "/exact/", http.StatusOK, ""},
+		{http.MethodGet, "/exact/foo", http.StatusNotFound, "404 - Page not found!"},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(test.method, test.path, nil))
+
+		if w.Code != test.status {
+			t.Errorf("route '%s %s' should be %d, router responded with: %d", test.method, test.path, test.status, w.Code)
+		}
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("route '%s %s' responded with '%s' and should be '%s'", test.method, test.path, body, test.expected)
+		}
+	}
+
+	router.Syntax = SyntaxServeMux
+	router.Register("/static/", testHandler())
+
+	for _, path := range []string{"/static/", "/static/css/app.css"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, path, nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("route '%s' should be 200, router responded with: %d", path, w.Code)
+		}
+	}
+
+	if _, err := router.Handle(http.MethodGet, "/users/:id/files", testHandler()); !errors.Is(err, ErrPattern) {
+		t.Errorf("router notation should fail with ServeMux syntax, error is: %v", err)
+	}
+
+	// named keys and paths are matched first, shadowed patterns are rejected
+	router.CollectErrors = true
+
+	if err := router.Register("GET /users/new", testHandler()); !errors.Is(err, ErrShadowed) {
+		t.Errorf("shadowed pattern should fail, error is: %v", err)
+	}
+
+	// the catch-all is assigned as 404 handler
+	if err := router.Register("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("INDEX"))
+	})); err != nil {
+		t.Errorf("catch-all pattern failed --> %s", err.Error())
+	}
+
+	for _, path := range []string{"/", "/about", "/users/42/files"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if body := w.Body.String(); w.Code != http.StatusOK || body != "INDEX" {
+			t.Errorf("route '%s' should be served by the catch-all, router responded with %d '%s'", path, w.Code, body)
+		}
+	}
+
+	// the handler is assigned for all methods or none
+	router.Register("PUT /atomic/{id}", testHandler())
+
+	if err := router.Register("/atomic/{id}", testHandler()); !errors.Is(err, ErrAlreadyAssigned) {
+		t.Errorf("pattern should fail as already assigned, error is: %v", err)
+	}
+
+	if m := router.trie.get(http.MethodGet, splitPath("/atomic/42")); m.handler != nil {
+		t.Error("failed pattern should not be assigned for any method")
+	}
+}
+
+func TestPrefixFallback(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	fallbackHandler := func(status int, body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		})
+	}
+
+	okHandler := fallbackHandler(http.StatusOK, "OK")
+
+	router.Get("/", okHandler)
+	router.Get("/api/users/:id", okHandler)
+	router.Post("/api/users", okHandler)
+	router.Get("/website/users", okHandler)
+	subRouter.Get("/status", okHandler)
+	router.Route("/api/v2", subRouter)
+
+	router.NotFound("/", fallbackHandler(http.StatusNotFound, "HTML_404"))
+	router.NotFound("/api", fallbackHandler(http.StatusNotFound, "JSON_404"))
+	router.MethodNotAllowed("/api", fallbackHandler(http.StatusMethodNotAllowed, "JSON_405"))
+
+	tests := []struct {
+		method   string
+		path     string
+		status   int
+		expected string
+	}{
+		{http.MethodGet, "/api/users/42", http.StatusOK, "OK"},
+		{http.MethodGet, "/unknown", http.StatusNotFound, "HTML_404"},
+		{http.MethodGet, "/website/unknown", http.StatusNotFound, "HTML_404"},
+		{http.MethodGet, "/api", http.StatusNotFound, "JSON_404"},
+		{http.MethodGet, "/api/unknown", http.StatusNotFound, "JSON_404"},
+		{http.MethodGet, "/api/users/42/unknown", http.StatusNotFound, "JSON_404"},
+		{http.MethodGet, "/api/v2/unknown", http.StatusNotFound, "JSON_404"},
+		{http.MethodGet, "/api/users", http.StatusMethodNotAllowed, "JSON_405"},
+		{http.MethodPost, "/api/v2/status", http.StatusMethodNotAllowed, "JSON_405"},
+		{http.MethodPost, "/website/users", http.StatusNotFound, "HTML_404"},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(test.method, test.path, nil))
+
+		if w.Code != test.status {
+			t.Errorf("%s '%s' should be %d, router responded with: %d", test.method, test.path, test.status, w.Code)
+		}
+
+		if body := w.Body.String(); body != test.expected {
+			t.Errorf("%s '%s' responded with '%s' and should be '%s'", test.method, test.path, body, test.expected)
+		}
+	}
+
+	if _, ok := router.Match(http.MethodGet, "/api"); ok {
+		t.Errorf("prefix '/api' should not match a route")
+	}
+
+	if err := router.Freeze(); err != nil {
+		t.Fatalf("freeze failed: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/unknown", nil))
+
+	if body := w.Body.String(); body != "JSON_404" {
+		t.Errorf("frozen router responded with '%s' and should be 'JSON_404'", body)
+	}
+
+	router = New()
+	router.Route("/api/v2", subRouter)
+	router.NotFound("/api", okHandler)
+
+	err := router.addFallback("/api", okHandler, false)
+
+	if !errors.Is(err, ErrAlreadyAssigned) {
+		t.Errorf("error should be '%v', got: %v", ErrAlreadyAssigned, err)
+	}
+
+	err = router.addFallback("/api/v2/status", okHandler, false)
+
+	if !errors.Is(err, ErrAssignment) {
+		t.Errorf("error should be '%v', got: %v", ErrAssignment, err)
+	}
+}
@@ -0,0 +1,134 @@
+package lionrouter
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the maximum count of routes suggested for a path not found
+const maxSuggestions = 3
+
+// Suggestions extracts the routes suggested for a path not found from the given context
+// It returns the paths of the suggested routes or nil
+func Suggestions(ctx context.Context) []string {
+	suggestions, _ := ctx.Value(contextSuggestionsKey).([]string)
+
+	return suggestions
+}
+
+// suggestRoutes reports whether the router or its parent routers suggest routes for paths not found
+func (r *Router) suggestRoutes(scope *mountScope) bool {
+	return r.SuggestRoutes || (scope != nil && scope.suggestRoutes)
+}
+
+// suggest retrieves the registered routes closest to the path not found by the match,
+// the key not found is replaced by the static keys of the node the walk failed at, the rest of the path is kept
+// prefix is the path matched by parent routers, parsed the split path left to the router
+// returns the escaped paths of the suggested routes ordered by edit distance or nil
+func (r *Router) suggest(prefix string, parsed []string, m *trieMatch) []string {
+	if m.miss == nil || parsed[m.missDepth] == "" {
+		return nil
+	}
+
+	key := strings.ToLower(parsed[m.missDepth])
+
+	// casing typos differ by distance 0, longer keys tolerate more typos
+	limit := 1 + len(key)/4
+
+	type suggestion struct {
+		path     string
+		distance int
+	}
+
+	var suggestions []suggestion
+
+	candidate := append([]string{}, parsed...)
+
+	for child := range m.miss.children {
+		d := editDistance(key, strings.ToLower(child))
+
+		if d > limit {
+			continue
+		}
+
+		// the path with the replaced key must match a route
+		candidate[m.missDepth] = child
+		cm := r.trie.get("", candidate)
+
+		if cm.node == nil || (cm.node.leaf == nil && cm.node.router == nil) {
+			continue
+		}
+
+		path := prefix + "/" + strings.Join(candidate, "/")
+		suggestions = append(suggestions, suggestion{(&url.URL{Path: path}).EscapedPath(), d})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+
+		return suggestions[i].path < suggestions[j].path
+	})
+
+	var paths []string
+
+	for i := 0; i < len(suggestions) && i < maxSuggestions; i++ {
+		paths = append(paths, suggestions[i].path)
+	}
+
+	return paths
+}
+
+// withSuggestions adds the routes suggested for the path not found to the context and as Link headers to the response
+// returns the context
+func (r *Router) withSuggestions(ctx context.Context, w http.ResponseWriter, req *http.Request, path string, parsed []string, m *trieMatch) context.Context {
+	// path matched by parent routers
+	prefix := strings.TrimSuffix(req.URL.Path[:len(req.URL.Path)-len(path)], "/")
+
+	suggestions := r.suggest(prefix, parsed, m)
+
+	if suggestions == nil {
+		return ctx
+	}
+
+	for _, s := range suggestions {
+		w.Header().Add("Link", "<"+s+">; rel=\"related\"")
+	}
+
+	return context.WithValue(ctx, contextSuggestionsKey, suggestions)
+}
+
+// editDistance calculates the Levenshtein distance of the given strings
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// distances of the previous and the current row
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,110 @@
+package lionrouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		distance int
+	}{
+		{"", "", 0},
+		{"users", "users", 0},
+		{"user", "users", 1},
+		{"usres", "users", 2},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"übung", "ubung", 1},
+	}
+
+	for _, test := range tests {
+		if d := editDistance(test.a, test.b); d != test.distance {
+			t.Errorf("distance of '%s' and '%s' is %d and should be %d", test.a, test.b, d, test.distance)
+		}
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	router.Get("/api/users/:id", handler)
+	router.Get("/api/user-groups/:id", handler)
+	router.Get("/api/orders", handler)
+	router.Get("/api/order-items", handler)
+	router.Get("/api/files/a b", handler)
+	subRouter.Get("/projects", handler)
+	router.Route("/tenants/:tenant", subRouter)
+
+	router.SuggestRoutes = true
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"/api/user/42", []string{"/api/users/42"}},
+		{"/api/Users/42", []string{"/api/users/42"}},
+		{"/API/orders", []string{"/api/orders"}},
+		{"/api/order", []string{"/api/orders"}},
+		{"/api/user/42/unknown", nil},
+		{"/api/fies/a b", []string{"/api/files/a%20b"}},
+		{"/api/unknown", nil},
+		{"/tenants/acme/project", []string{"/tenants/acme/projects"}},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.Path = test.path
+
+		var suggestions []string
+
+		router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			suggestions = Suggestions(r.Context())
+		})
+
+		router.ServeHTTP(w, req)
+
+		if !reflect.DeepEqual(suggestions, test.expected) {
+			t.Errorf("route '%s' suggested %v and should be %v", test.path, suggestions, test.expected)
+		}
+
+		if links := w.Header().Values("Link"); len(links) != len(test.expected) {
+			t.Errorf("route '%s' sent links %v", test.path, links)
+		} else if len(links) > 0 && links[0] != "<"+test.expected[0]+">; rel=\"related\"" {
+			t.Errorf("route '%s' sent link '%s'", test.path, links[0])
+		}
+	}
+
+	// suggestions are part of the problem details
+	router.NotFoundHandler = nil
+	router.ProblemDetails = true
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/42", nil))
+
+	var problem struct {
+		Detail      string   `json:"detail"`
+		Suggestions []string `json:"suggestions"`
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("invalid problem details: %v", err)
+	}
+
+	if problem.Detail != "No route matches GET /api/user/42, did you mean /api/users/42?" {
+		t.Errorf("problem detail is '%s'", problem.Detail)
+	}
+
+	if !reflect.DeepEqual(problem.Suggestions, []string{"/api/users/42"}) {
+		t.Errorf("problem suggestions are %v", problem.Suggestions)
+	}
+}
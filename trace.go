@@ -0,0 +1,82 @@
+package lionrouter
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// Tracer
+// interface starts the spans of the requests served by the router
+type Tracer interface {
+	// StartSpan starts a span for the request and returns the context holding it
+	// The name of the dispatch span is the matched route pattern, NotFound or MethodNotAllowed,
+	// middleware spans are named "middleware" followed by the name or position of the middleware
+	StartSpan(ctx context.Context, name string, req *http.Request) (context.Context, Span)
+}
+
+// Span
+// interface is a span started by a Tracer
+type Span interface {
+	// SetName renames the span, sub-routers rename the dispatch span to the pattern matched by the innermost router
+	SetName(name string)
+
+	// SetStatus records the status code of the response
+	SetStatus(status int)
+
+	// End finishes the span
+	End()
+}
+
+// tracer retrieves the tracer of the router or the one inherited from parent routers
+// returns the tracer or nil if not tracing
+func (r *Router) tracer(scope *mountScope) Tracer {
+	if r.Tracer != nil {
+		return r.Tracer
+	}
+
+	if scope != nil {
+		return scope.tracer
+	}
+
+	return nil
+}
+
+// traceDispatch starts the dispatch span named after the route captured so far
+// returns the wrapped writer, the context holding the span and the function to call once the request is served
+func traceDispatch(tracer Tracer, w http.ResponseWriter, ctx context.Context, req *http.Request, capture *routeCapture) (http.ResponseWriter, context.Context, func(served bool)) {
+	name := capture.label()
+	ctx, span := tracer.StartSpan(ctx, name, req)
+	rw := newResponseWriter(w)
+
+	return rw.wrapped, ctx, func(served bool) {
+		// sub-routers matched a more specific route
+		if label := capture.label(); label != name {
+			span.SetName(label)
+		}
+
+		span.SetStatus(rw.servedStatus(served))
+		span.End()
+	}
+}
+
+// traceMiddleware wraps the handler returned by the middleware with a span
+func traceMiddleware(tracer Tracer, name string, handler http.Handler) http.Handler {
+	name = "middleware " + name
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, span := tracer.StartSpan(req.Context(), name, req)
+		defer span.End()
+
+		handler.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// middlewareName retrieves the name of the middleware entry for spans, unnamed middleware is named after its position
+func middlewareName(entry middlewareEntry, position int) string {
+	if entry.name != "" {
+		return entry.name
+	}
+
+	return strconv.Itoa(position)
+}
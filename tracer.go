@@ -0,0 +1,269 @@
+package lionrouter
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSpanLimit is the count of spans kept in memory by a MemoryTracer if no limit is set
+const DefaultSpanLimit = 1024
+
+// SpanContext
+// struct identifies a span across process boundaries as defined by W3C Trace Context
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+
+	// Flags holds the trace flags, 01 if sampled
+	Flags byte
+
+	// TraceState holds the vendor specific trace state passed through unchanged
+	TraceState string
+}
+
+// IsValid reports whether trace and span id are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// Traceparent formats the span context as traceparent header value
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%x-%x-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// ParseTraceparent parses the given traceparent and tracestate header values
+// It returns the span context of the remote parent and true or an empty span context and false if invalid
+func ParseTraceparent(traceparent string, tracestate string) (SpanContext, bool) {
+	tp := strings.TrimSpace(traceparent)
+
+	// version-traceid-parentid-flags, later versions may append fields
+	if len(tp) < 55 || tp[2] != '-' || tp[35] != '-' || tp[52] != '-' || (len(tp) > 55 && tp[55] != '-') {
+		return SpanContext{}, false
+	}
+
+	version := tp[:2]
+
+	if !isLowerHex(version) || version == "ff" || (version == "00" && len(tp) != 55) {
+		return SpanContext{}, false
+	}
+
+	var sc SpanContext
+	var flags [1]byte
+
+	if !decodeLowerHex(sc.TraceID[:], tp[3:35]) || !decodeLowerHex(sc.SpanID[:], tp[36:52]) || !decodeLowerHex(flags[:], tp[53:55]) {
+		return SpanContext{}, false
+	}
+
+	if !sc.IsValid() {
+		return SpanContext{}, false
+	}
+
+	sc.Flags = flags[0]
+	sc.TraceState = strings.TrimSpace(tracestate)
+
+	return sc, true
+}
+
+// isLowerHex reports whether the string consists of lowercase hex digits only
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decodeLowerHex decodes the lowercase hex string into the given bytes
+func decodeLowerHex(dst []byte, s string) bool {
+	if !isLowerHex(s) {
+		return false
+	}
+
+	_, err := hex.Decode(dst, []byte(s))
+
+	return err == nil
+}
+
+// SpanContextFrom extracts the span context of the current span started by a MemoryTracer from the given context
+// It returns the span context and true or an empty span context and false if no span is started
+func SpanContextFrom(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(contextSpanKey).(SpanContext)
+
+	return sc, ok
+}
+
+// InjectTraceContext sets the traceparent and tracestate headers of the current span in the given context,
+// used to propagate the trace to outgoing requests
+func InjectTraceContext(ctx context.Context, header http.Header) {
+	sc, ok := SpanContextFrom(ctx)
+
+	if !ok {
+		return
+	}
+
+	header.Set("traceparent", sc.Traceparent())
+
+	if sc.TraceState != "" {
+		header.Set("tracestate", sc.TraceState)
+	} else {
+		header.Del("tracestate")
+	}
+}
+
+// RecordedSpan
+// struct holds a span finished by a MemoryTracer
+type RecordedSpan struct {
+	Name        string
+	SpanContext SpanContext
+
+	// Parent holds the span context of the parent span, remote or local, empty for root spans
+	Parent SpanContext
+
+	Start time.Time
+	End   time.Time
+
+	// Status code of the response, 0 for middleware spans
+	Status int
+}
+
+// SpanExporter
+// interface receives the spans finished by a MemoryTracer, e.g. to send them to a tracing backend
+// ExportSpan is called while serving the request and must not block
+type SpanExporter interface {
+	ExportSpan(span RecordedSpan)
+}
+
+// MemoryTracer
+// struct is a Tracer propagating W3C traceparent and tracestate headers, the finished spans are kept in memory
+// and passed through the exporter
+type MemoryTracer struct {
+	// Exporter receives each finished span, nil to keep spans in memory only
+	Exporter SpanExporter
+
+	// Limit is the count of spans kept in memory, the oldest spans are dropped, DefaultSpanLimit if not set
+	Limit int
+
+	mutex sync.Mutex
+	spans []RecordedSpan
+}
+
+// NewMemoryTracer creates a new tracer passing the finished spans through the given exporter
+// returns tracer instance
+func NewMemoryTracer(exporter SpanExporter) *MemoryTracer {
+	return &MemoryTracer{
+		Exporter: exporter,
+		Limit:    DefaultSpanLimit,
+	}
+}
+
+// StartSpan starts a span as child of the current span in the context, or of the remote parent
+// passed through the traceparent header of the request, a new trace is started otherwise
+func (t *MemoryTracer) StartSpan(ctx context.Context, name string, req *http.Request) (context.Context, Span) {
+	parent, ok := SpanContextFrom(ctx)
+
+	if !ok {
+		parent, ok = ParseTraceparent(req.Header.Get("traceparent"), req.Header.Get("tracestate"))
+	}
+
+	sc := SpanContext{Flags: 0x01}
+
+	if ok {
+		sc.TraceID, sc.Flags, sc.TraceState = parent.TraceID, parent.Flags, parent.TraceState
+	} else {
+		putRandom(sc.TraceID[:])
+	}
+
+	putRandom(sc.SpanID[:])
+
+	span := &memorySpan{
+		tracer: t,
+		data: RecordedSpan{
+			Name:        name,
+			SpanContext: sc,
+			Parent:      parent,
+			Start:       time.Now(),
+		},
+	}
+
+	return context.WithValue(ctx, contextSpanKey, sc), span
+}
+
+// Spans retrieves the finished spans kept in memory, oldest first
+func (t *MemoryTracer) Spans() []RecordedSpan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return append([]RecordedSpan{}, t.spans...)
+}
+
+// record keeps the finished span in memory and passes it through the exporter
+func (t *MemoryTracer) record(span RecordedSpan) {
+	t.mutex.Lock()
+
+	limit := t.Limit
+
+	if limit <= 0 {
+		limit = DefaultSpanLimit
+	}
+
+	t.spans = append(t.spans, span)
+
+	if len(t.spans) > limit {
+		t.spans = append(t.spans[:0], t.spans[len(t.spans)-limit:]...)
+	}
+
+	t.mutex.Unlock()
+
+	if t.Exporter != nil {
+		t.Exporter.ExportSpan(span)
+	}
+}
+
+// putRandom fills the given id with random bytes, never all zero
+func putRandom(id []byte) {
+	for {
+		for i := range id {
+			id[i] = byte(rand.Uint32())
+		}
+
+		for _, b := range id {
+			if b != 0 {
+				return
+			}
+		}
+	}
+}
+
+// memorySpan
+// struct is a span started by a MemoryTracer
+type memorySpan struct {
+	tracer *MemoryTracer
+	data   RecordedSpan
+	ended  bool
+}
+
+func (s *memorySpan) SetName(name string) {
+	s.data.Name = name
+}
+
+func (s *memorySpan) SetStatus(status int) {
+	s.data.Status = status
+}
+
+func (s *memorySpan) End() {
+	if s.ended {
+		return
+	}
+
+	s.ended = true
+	s.data.End = time.Now()
+	s.tracer.record(s.data)
+}
@@ -0,0 +1,152 @@
+package lionrouter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		traceparent string
+		valid       bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{" 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00 ", true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		sc, ok := ParseTraceparent(test.traceparent, "vendor=value")
+
+		if ok != test.valid {
+			t.Errorf("traceparent '%s' valid is %t and should be %t", test.traceparent, ok, test.valid)
+		}
+
+		if ok && sc.TraceState != "vendor=value" {
+			t.Errorf("traceparent '%s' parsed tracestate '%s'", test.traceparent, sc.TraceState)
+		}
+	}
+
+	sc, _ := ParseTraceparent(tests[0].traceparent, "")
+
+	if tp := sc.Traceparent(); tp != tests[0].traceparent {
+		t.Errorf("traceparent formatted as '%s' and should be '%s'", tp, tests[0].traceparent)
+	}
+}
+
+type exporterFunc func(span RecordedSpan)
+
+func (f exporterFunc) ExportSpan(span RecordedSpan) {
+	f(span)
+}
+
+func TestTracer(t *testing.T) {
+	router := New()
+	subRouter := New()
+
+	exported := 0
+	tracer := NewMemoryTracer(exporterFunc(func(span RecordedSpan) {
+		exported++
+	}))
+
+	var outgoing http.Header
+
+	middleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	subRouter.Get("/users/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outgoing = make(http.Header)
+		InjectTraceContext(r.Context(), outgoing)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	subRouter.UseNamed("auth", middleware)
+	router.Use(middleware)
+	router.Route("/tenants/:tenant", subRouter)
+
+	router.Tracer = tracer
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/acme/users/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("tracestate", "vendor=value")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := tracer.Spans()
+
+	// spans are recorded as they end, innermost first
+	names := []string{"middleware auth", "middleware 0", "/tenants/:tenant/users/:id"}
+
+	if len(spans) != len(names) || exported != len(names) {
+		t.Fatalf("recorded %d and exported %d spans, should be %d", len(spans), exported, len(names))
+	}
+
+	for i, name := range names {
+		if spans[i].Name != name {
+			t.Errorf("span %d is named '%s' and should be '%s'", i, spans[i].Name, name)
+		}
+
+		if spans[i].SpanContext.Traceparent()[3:35] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("span '%s' should continue the remote trace", spans[i].Name)
+		}
+	}
+
+	dispatch := spans[2]
+
+	if dispatch.Parent.Traceparent() != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("dispatch span parent is '%s'", dispatch.Parent.Traceparent())
+	}
+
+	if spans[1].Parent.SpanID != dispatch.SpanContext.SpanID || spans[0].Parent.SpanID != spans[1].SpanContext.SpanID {
+		t.Errorf("middleware spans should be nested in the dispatch span")
+	}
+
+	if dispatch.Status != http.StatusAccepted {
+		t.Errorf("dispatch span status is %d and should be %d", dispatch.Status, http.StatusAccepted)
+	}
+
+	if tp := outgoing.Get("traceparent"); tp != spans[0].SpanContext.Traceparent() || outgoing.Get("tracestate") != "vendor=value" {
+		t.Errorf("outgoing trace context is '%s' and should be '%s'", tp, spans[0].SpanContext.Traceparent())
+	}
+
+	// requests without route start a new trace
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	spans = tracer.Spans()
+	notFound := spans[len(spans)-1]
+
+	if notFound.Name != "NotFound" || notFound.Status != http.StatusNotFound || notFound.Parent.IsValid() {
+		t.Errorf("unmatched span is %+v", notFound)
+	}
+
+	if _, ok := SpanContextFrom(context.Background()); ok {
+		t.Errorf("background context should not hold a span")
+	}
+}
+
+func TestTracerLimit(t *testing.T) {
+	tracer := &MemoryTracer{Limit: 2}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	for _, name := range []string{"a", "b", "c"} {
+		_, span := tracer.StartSpan(context.Background(), name, req)
+		span.End()
+		span.End()
+	}
+
+	spans := tracer.Spans()
+
+	if len(spans) != 2 || spans[0].Name != "b" || spans[1].Name != "c" {
+		t.Errorf("tracer should keep the latest 2 spans, got: %+v", spans)
+	}
+}
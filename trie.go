@@ -3,11 +3,15 @@ package lionrouter
 import (
 	"github.com/oxtoacart/bpool"
 	"net/http"
+	"sort"
 )
 
 type trie struct {
 	root  *node
 	bpool *bpool.BufferPool
+
+	// frozen tree is read-only
+	frozen bool
 }
 
 func newTrie() *trie {
@@ -17,25 +21,34 @@ func newTrie() *trie {
 	}
 }
 
-func (t *trie) walkAdd(path string) (*node, error) {
+// walkAdd walks the tree along the given pattern and creates missing nodes
+// returns the node or a *RouteError if the pattern conflicts with assigned routes
+func (t *trie) walkAdd(method string, path string) (*node, error) {
 	// parse path
 	parsed := parsePath(path)
 
+	// check for invalid patterns before the tree is modified
+	if err := validateKeys(parsed); err != nil {
+		return nil, &RouteError{method, path, "", err}
+	}
+
+	// check for conflicts with assigned routes before the tree is modified
+	if err := t.conflict(method, path, parsed); err != nil {
+		return nil, err
+	}
+
 	// set cursor tree root
 	cursor := t.root
 
 	// walk tree
 	for _, key := range parsed {
-		// if router is set, don't walk tree
-		if cursor.router != nil {
-			return nil, ErrAssignment
-		}
-
 		// check if key is wildcard or wildcard path
 		if key.wildcard || key.wildcardPath {
 			// create wildcard node if nil
 			if cursor.wildcard == nil {
 				cursor.wildcard = newNode(key)
+				cursor.wildcard.pattern = path
+				cursor.wildcard.route = cursor.route + "/" + key.String()
 			}
 
 			// walkAdd tree using wildcard node
@@ -53,6 +66,8 @@ func (t *trie) walkAdd(path string) (*node, error) {
 			// create node entry in map if nil
 			if cursor.children[key.name] == nil {
 				cursor.children[key.name] = newNode(key)
+				cursor.children[key.name].pattern = path
+				cursor.children[key.name].route = cursor.route + "/" + key.String()
 			}
 
 			// walkAdd tree using node map
@@ -63,14 +78,84 @@ func (t *trie) walkAdd(path string) (*node, error) {
 	return cursor, nil
 }
 
-func (t *trie) walkGet(path string) (*node, RouterParam) {
-	// split path
-	parsed := splitPath(path)
+// conflict walks the assigned nodes along the given parsed pattern without modifying the tree
+// returns a *RouteError if the pattern passes a sub-router or conflicts with a named key or path at the same position
+func (t *trie) conflict(method string, path string, parsed []*pathKey) error {
+	cursor := t.root
+
+	for _, key := range parsed {
+		// if router is set, don't walk tree
+		if cursor.router != nil {
+			return &RouteError{method, path, cursor.pattern, ErrAssignment}
+		}
 
+		if key.wildcard || key.wildcardPath {
+			if cursor.wildcard == nil {
+				return nil
+			}
+
+			// wildcards at the same position must share name and type
+			if *cursor.wildcard.key != *key {
+				return &RouteError{method, path, cursor.wildcard.pattern, ErrParamConflict}
+			}
+
+			cursor = cursor.wildcard
+
+			if key.wildcardPath {
+				return nil
+			}
+		} else {
+			if cursor.children[key.name] == nil {
+				return nil
+			}
+
+			cursor = cursor.children[key.name]
+		}
+	}
+
+	return nil
+}
+
+// shadowed walks the assigned nodes along the given parsed pattern without modifying the tree
+// returns a *RouteError if static keys and named keys or paths would share a position, named keys and paths are matched first
+func (t *trie) shadowed(method string, path string, parsed []*pathKey) error {
+	cursor := t.root
+
+	for _, key := range parsed {
+		if cursor == nil || cursor.router != nil {
+			return nil
+		}
+
+		if key.wildcard || key.wildcardPath {
+			// the named key or path would shadow the assigned static keys
+			for _, child := range cursor.children {
+				return &RouteError{method, path, child.assignedPattern(), ErrShadowed}
+			}
+
+			cursor = cursor.wildcard
+		} else {
+			// the static key would be shadowed by the assigned named key or path
+			if cursor.wildcard != nil {
+				return &RouteError{method, path, cursor.wildcard.assignedPattern(), ErrShadowed}
+			}
+
+			cursor = cursor.children[key.name]
+		}
+	}
+
+	return nil
+}
+
+// walkGet walks the tree along the given split path and fills the given match
+// sets the node, the params in capture order, the count of path keys consumed by the walk
+// and the 404/405 handlers of the deepest node visited, even if no node is found
+func (t *trie) walkGet(parsed []string, m *trieMatch) {
 	// set cursor tree root
 	cursor := t.root
+	m.visit(cursor)
 
-	param := make(RouterParam)
+	// count of path keys consumed
+	depth := 0
 
 	for i, key := range parsed {
 		// if router is set, don't walk tree
@@ -78,12 +163,18 @@ func (t *trie) walkGet(path string) (*node, RouterParam) {
 			break
 		}
 
+		depth++
+
 		// check for wildcard child
 		if cursor.wildcard != nil {
 			cursor = cursor.wildcard
+			m.visit(cursor)
 
 			// check whether wildcard is single key or whole path
-			if cursor.key.wildcardPath {
+			if cursor.key.wildcardPath && cursor.key.name == "" {
+				// anonymous path, nothing to capture
+				break
+			} else if cursor.key.wildcardPath {
 				// get buffer from pool
 				buffer := t.bpool.Get()
 
@@ -93,8 +184,8 @@ func (t *trie) walkGet(path string) (*node, RouterParam) {
 					buffer.WriteString(s)
 				}
 
-				// set wildcard path in param map
-				param[cursor.key.name] = buffer.String()
+				// add wildcard path to params
+				m.list = append(m.list, ParamEntry{cursor.key.name, buffer.String()})
 
 				// put back buffer to pool
 				t.bpool.Put(buffer)
@@ -102,30 +193,84 @@ func (t *trie) walkGet(path string) (*node, RouterParam) {
 				// break walking the tree
 				break
 			} else {
-				// set current wildcard key in param map
-				param[cursor.key.name] = key
+				// add current wildcard key to params
+				m.list = append(m.list, ParamEntry{cursor.key.name, key})
 			}
 		} else if cursor.children != nil && cursor.children[key] != nil {
 			// access child through key name
 			cursor = cursor.children[key]
+			m.visit(cursor)
 		} else {
-			// return because no node found
-			return nil, nil
+			// return because no node found, keep the position for suggestions
+			m.list = nil
+			m.miss, m.missDepth = cursor, i
+			return
 		}
 	}
 
-	// return the node and param map
-	return cursor, param
+	// anonymous path matches the subtree root as well
+	if cursor.leaf == nil && cursor.router == nil && cursor.wildcard != nil && cursor.wildcard.key.wildcardPath && cursor.wildcard.key.name == "" {
+		cursor = cursor.wildcard
+		m.visit(cursor)
+	}
+
+	// set the node and consumed depth
+	m.node = cursor
+	m.depth = depth
 }
 
-func (t *trie) addHandler(method string, path string, handler http.Handler) error {
+// checkHandler checks whether the handler can be assigned for given method and route without modifying the tree
+// returns a *RouteError if the assignment would fail
+func (t *trie) checkHandler(method string, path string, handler http.Handler) error {
+	// check if tree is read-only
+	if t.frozen {
+		return &RouteError{method, path, "", ErrFrozen}
+	}
+
 	// check if handler not nil
 	if handler == nil {
-		return ErrNilHandler
+		return &RouteError{method, path, "", ErrNilHandler}
+	}
+
+	// check method
+	if _, err := handlerMethodFromString(method); err != nil {
+		return &RouteError{method, path, "", err}
+	}
+
+	parsed := parsePath(path)
+
+	if err := validateKeys(parsed); err != nil {
+		return &RouteError{method, path, "", err}
+	}
+
+	if err := t.conflict(method, path, parsed); err != nil {
+		return err
+	}
+
+	// check assignments of an existing node
+	if cursor := t.find(parsed); cursor != nil {
+		if cursor.router != nil {
+			return &RouteError{method, path, cursor.pattern, ErrAssignment}
+		}
+
+		if cursor.leaf != nil {
+			if h, _ := cursor.leaf.getHandler(method); h != nil {
+				return &RouteError{method, path, cursor.pattern, ErrAlreadyAssigned}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *trie) addHandler(method string, path string, handler http.Handler) error {
+	// check before the tree is modified
+	if err := t.checkHandler(method, path, handler); err != nil {
+		return err
 	}
 
 	// walk tree
-	cursor, err := t.walkAdd(path)
+	cursor, err := t.walkAdd(method, path)
 
 	// check for errors
 	if err != nil {
@@ -134,12 +279,13 @@ func (t *trie) addHandler(method string, path string, handler http.Handler) erro
 
 	// check again if router is set and return if set
 	if cursor.router != nil {
-		return ErrAssignment
+		return &RouteError{method, path, cursor.pattern, ErrAssignment}
 	}
 
-	// create leaf if nil
+	// create leaf if nil, the node pattern is set to the first route assigned
 	if cursor.leaf == nil {
 		cursor.leaf = newLeaf()
+		cursor.pattern = path
 	}
 
 	// add handler to leaf
@@ -147,7 +293,7 @@ func (t *trie) addHandler(method string, path string, handler http.Handler) erro
 
 	// check for errors
 	if err != nil {
-		return err
+		return &RouteError{method, path, cursor.pattern, err}
 	}
 
 	// handler added, no errors
@@ -155,13 +301,23 @@ func (t *trie) addHandler(method string, path string, handler http.Handler) erro
 }
 
 func (t *trie) addRouter(path string, router http.Handler) error {
+	// check if tree is read-only
+	if t.frozen {
+		return &RouteError{"", path, "", ErrFrozen}
+	}
+
 	// check if handler not nil
 	if router == nil {
-		return ErrNilHandler
+		return &RouteError{"", path, "", ErrNilHandler}
+	}
+
+	// named paths cannot be used for sub-routers
+	if keys := parsePath(path); keys[len(keys)-1].wildcardPath {
+		return &RouteError{"", path, "", ErrAssignment}
 	}
 
 	// walk tree
-	cursor, err := t.walkAdd(path)
+	cursor, err := t.walkAdd("", path)
 
 	// check for errors
 	if err != nil {
@@ -170,41 +326,214 @@ func (t *trie) addRouter(path string, router http.Handler) error {
 
 	// check if no leaf/wildcard/children/router are set and return if set
 	if cursor.leaf != nil || cursor.wildcard != nil || cursor.children != nil || cursor.router != nil {
-		return ErrAssignment
+		return &RouteError{"", path, cursor.assignedPattern(), ErrAssignment}
 	}
 
 	// add router
 	cursor.router = router
+	cursor.pattern = path
 
 	// router added, no errors
 	return nil
 }
 
-func (t *trie) get(method string, path string) (http.Handler, RouterParam) {
+// addFallback assigns the 404 or 405 handler for the subtree of the given path
+func (t *trie) addFallback(path string, handler http.Handler, methodNotAllowed bool) error {
+	// check if tree is read-only
+	if t.frozen {
+		return &RouteError{"", path, "", ErrFrozen}
+	}
+
+	// check if handler not nil
+	if handler == nil {
+		return &RouteError{"", path, "", ErrNilHandler}
+	}
+
+	// the root prefix is assigned to the tree root, it is visited by every request
+	cursor := t.root
+
+	if path != "" && path != "/" {
+		var err error
+
+		// walk tree
+		cursor, err = t.walkAdd("", path)
+
+		// check for errors
+		if err != nil {
+			return err
+		}
+	}
+
+	// the sub-router handles the subtree itself
+	if cursor.router != nil {
+		return &RouteError{"", path, cursor.pattern, ErrAssignment}
+	}
+
+	// add handler
+	if methodNotAllowed {
+		if cursor.methodNotAllowed != nil {
+			return &RouteError{"", path, "", ErrAlreadyAssigned}
+		}
+
+		cursor.methodNotAllowed = handler
+	} else {
+		if cursor.notFound != nil {
+			return &RouteError{"", path, "", ErrAlreadyAssigned}
+		}
+
+		cursor.notFound = handler
+	}
+
+	// handler added, no errors
+	return nil
+}
+
+// find retrieves the node of the given parsed pattern without walking sub-routers
+// returns nil if no node exists
+func (t *trie) find(keys []*pathKey) *node {
+	cursor := t.root
+
+	for _, key := range keys {
+		if key.wildcard || key.wildcardPath {
+			cursor = cursor.wildcard
+		} else {
+			cursor = cursor.children[key.name]
+		}
+
+		if cursor == nil {
+			return nil
+		}
+	}
+
+	return cursor
+}
+
+// trieMatch
+// struct holds the result of a tree lookup
+type trieMatch struct {
+	// node found for the path, nil if no node found
+	node *node
+
+	// handler assigned for the method or sub-router, nil if not assigned
+	handler http.Handler
+
+	params RouterParam
+
+	// params in capture order
+	list ParamList
+
+	// count of path keys matched by a sub-router, 0 if no sub-router matched
+	depth int
+
+	// 404 and 405 handlers of the deepest node visited with one assigned, nil if none
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+
+	// node without child for the path key at missDepth, nil if the walk didn't fail
+	miss      *node
+	missDepth int
+}
+
+// visit picks the 404 and 405 handlers of the given node if assigned
+func (m *trieMatch) visit(n *node) {
+	if n.notFound != nil {
+		m.notFound = n.notFound
+	}
+
+	if n.methodNotAllowed != nil {
+		m.methodNotAllowed = n.methodNotAllowed
+	}
+}
+
+// get retrieves the handler for given method and split path
+// returns the match, the node is set even if no handler for the method is assigned
+func (t *trie) get(method string, parsed []string) trieMatch {
+	var m trieMatch
+
 	// retrieve node from trie
-	node, param := t.walkGet(path)
+	t.walkGet(parsed, &m)
 
 	// check if node not nil
-	if node == nil {
-		return nil, nil
+	if m.node == nil {
+		return m
 	}
 
 	// check if sub-router exists
-	if node.router != nil {
-		return node.router, param
+	if m.node.router != nil {
+		m.handler = m.node.router
+		m.params = m.list.Map()
+		return m
 	}
 
+	// sub-router depth only
+	m.depth = 0
+
 	// check if node has leaf and retrieve handler from leaf
-	if node.leaf != nil {
-		h, err := node.leaf.getHandler(method)
+	if m.node.leaf != nil {
+		h, err := m.node.leaf.getHandler(method)
 
-		if err != nil {
-			return nil, nil
+		if err == nil {
+			m.handler = h
+			m.params = m.list.Map()
+			return m
+		}
+	}
+
+	// no handler assigned, params are set for matched routes only
+	m.list = nil
+	return m
+}
+
+// methods retrieves the methods assigned to the node
+// returns the methods or nil if no handler is assigned
+func (n *node) methods() []string {
+	// check if node has leaf
+	if n == nil || n.router != nil || n.leaf == nil {
+		return nil
+	}
+
+	return n.leaf.methods()
+}
+
+// walk visits each node of the tree depth first, children in sorted order
+// the callback gets the pattern of the node, the walk is aborted on error
+func (t *trie) walk(fn func(pattern string, n *node) error) error {
+	return walkNode(t.root, "", fn)
+}
+
+func walkNode(n *node, pattern string, fn func(pattern string, n *node) error) error {
+	// sort static children for a stable order
+	keys := make([]string, 0, len(n.children))
+
+	for k := range n.children {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		child := n.children[k]
+		childPattern := pattern + "/" + child.key.String()
+
+		if err := fn(childPattern, child); err != nil {
+			return err
+		}
+
+		if err := walkNode(child, childPattern, fn); err != nil {
+			return err
 		}
+	}
+
+	// wildcard is visited last
+	if n.wildcard != nil {
+		childPattern := pattern + "/" + n.wildcard.key.String()
 
-		return h, param
+		if err := fn(childPattern, n.wildcard); err != nil {
+			return err
+		}
+
+		return walkNode(n.wildcard, childPattern, fn)
 	}
 
-	// return node
-	return nil, nil
+	return nil
 }
@@ -1,6 +1,7 @@
 package lionrouter
 
 import (
+	"errors"
 	"net/http"
 	"testing"
 )
@@ -133,7 +134,8 @@ func TestTrieGet(t *testing.T) {
 	}
 
 	for i, r := range routesRetr {
-		handler, param := trie.get(http.MethodGet, r)
+		m := trie.get(http.MethodGet, splitPath(r))
+		handler, param := m.handler, m.params
 
 		if handler == nil {
 			t.Errorf("tree: error while retrieving handler '%s'", r)
@@ -202,7 +204,8 @@ func TestTrieGetRouter(t *testing.T) {
 	}
 
 	for i, r := range routesRetr {
-		handler, param := trie.get(http.MethodGet, r)
+		m := trie.get(http.MethodGet, splitPath(r))
+		handler, param := m.handler, m.params
 
 		if handler == nil {
 			t.Errorf("tree: error while retrieving handler '%s'", r)
@@ -219,3 +222,64 @@ func TestTrieGetRouter(t *testing.T) {
 		}
 	}
 }
+
+func TestTrieConflicts(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// do nothing
+		})
+	}
+
+	trie := newTrie()
+
+	trie.addHandler(http.MethodGet, "/u/:id", testHandler())
+	trie.addHandler(http.MethodGet, "/files/*path", testHandler())
+	trie.addRouter("/static", testHandler())
+
+	tests := []struct {
+		method   string
+		path     string
+		conflict string
+		err      error
+	}{
+		{http.MethodGet, "/u/:id/", "/u/:id", ErrAlreadyAssigned},
+		{http.MethodGet, "/u/:name/x", "/u/:id", ErrParamConflict},
+		{http.MethodGet, "/u/*name", "/u/:id", ErrParamConflict},
+		{http.MethodGet, "/a/:id/b/:id", "", ErrDuplicateParam},
+		{http.MethodGet, "/a/*path/b", "", ErrNamedPath},
+		{http.MethodGet, "/static/*file", "/static", ErrAssignment},
+		{"", "/files", "/files/*path", ErrAssignment},
+		{"", "/assets/*file", "", ErrAssignment},
+		{"PROPFIND", "/props", "", ErrUnknownHTTPMethod},
+	}
+
+	for _, test := range tests {
+		var err error
+
+		if test.method == "" {
+			err = trie.addRouter(test.path, testHandler())
+		} else {
+			err = trie.addHandler(test.method, test.path, testHandler())
+		}
+
+		var routeErr *RouteError
+
+		if !errors.As(err, &routeErr) {
+			t.Errorf("'%s %s' should fail with route error, error is: %v", test.method, test.path, err)
+			continue
+		}
+
+		if !errors.Is(err, test.err) {
+			t.Errorf("'%s %s' should fail with '%v', error is: %v", test.method, test.path, test.err, err)
+		}
+
+		if routeErr.Method != test.method || routeErr.Pattern != test.path || routeErr.Conflict != test.conflict {
+			t.Errorf("route error is '%s %s' conflicting '%s' and should be '%s %s' conflicting '%s'", routeErr.Method, routeErr.Pattern, routeErr.Conflict, test.method, test.path, test.conflict)
+		}
+	}
+
+	// failed assignments must not modify the tree
+	if trie.root.children["props"] != nil || trie.root.children["a"] != nil {
+		t.Error("failed assignment should not create nodes")
+	}
+}
@@ -0,0 +1,82 @@
+package lionrouter
+
+import "net/http"
+
+// RouteInfo
+// struct describes an assigned route
+type RouteInfo struct {
+	// Method of the route, empty for mounted handlers which are no lionrouter instance
+	Method string
+
+	// Pattern of the route including the prefix of parent routers
+	Pattern string
+
+	// Name of the route, empty if not named
+	Name string
+
+	Handler http.Handler
+
+	// Middleware applied to the route in execution order, outermost first
+	Middleware []Middleware
+}
+
+// WalkFunc
+// func is called by Walk for each assigned route
+type WalkFunc func(method string, pattern string, handler http.Handler, middleware []Middleware) error
+
+// Walk calls fn for each assigned route, routes of mounted lionrouter sub-routers are included
+// Mounted handlers which are no lionrouter instance are passed with empty method and the mount pattern
+// returns the first error returned by fn
+func (r *Router) Walk(fn WalkFunc) error {
+	return r.walk("", nil, func(route RouteInfo) error {
+		return fn(route.Method, route.Pattern, route.Handler, route.Middleware)
+	})
+}
+
+// Routes lists all assigned routes in walk order
+func (r *Router) Routes() []RouteInfo {
+	var routes []RouteInfo
+
+	r.walk("", nil, func(route RouteInfo) error {
+		routes = append(routes, route)
+		return nil
+	})
+
+	return routes
+}
+
+// walk walks the routes with the prefix and middleware of parent routers
+func (r *Router) walk(prefix string, parent []Middleware, fn func(route RouteInfo) error) error {
+	// parent middleware is executed first
+	middleware := append(append([]Middleware{}, parent...), r.middlewareStack()...)
+
+	return r.trie.walk(func(pattern string, n *node) error {
+		pattern = prefix + pattern
+
+		if n.router != nil {
+			if child, ok := n.router.(*Router); ok {
+				return child.walk(pattern, middleware, fn)
+			}
+
+			return fn(RouteInfo{"", pattern, "", n.router, middleware})
+		}
+
+		if n.leaf == nil {
+			return nil
+		}
+
+		for i, h := range n.leaf.handler {
+			if h == nil {
+				continue
+			}
+
+			method := leafMethods[i]
+
+			if err := fn(RouteInfo{method, pattern, n.leaf.getName(method), h, middleware}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
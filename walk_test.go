@@ -0,0 +1,78 @@
+package lionrouter
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRoutes(t *testing.T) {
+	testHandler := func() http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// do nothing
+		})
+	}
+
+	noop := func(next http.Handler) http.Handler {
+		return next
+	}
+
+	router := New()
+	subRouter := New()
+
+	router.Use(noop)
+	subRouter.Use(noop, noop)
+
+	router.Get("/", testHandler())
+	router.Post("/users/:id", testHandler())
+	router.Get("/users/:id", testHandler())
+	router.Get("/users/me", testHandler())
+	router.Get("/files/*path", testHandler())
+	router.Route("/static", testHandler())
+	router.Route("/tenants/:tenant", subRouter)
+	subRouter.Get("/", testHandler())
+	subRouter.Delete("/projects/:project", testHandler())
+
+	expected := []struct {
+		method     string
+		pattern    string
+		middleware int
+	}{
+		{http.MethodGet, "/", 1},
+		{http.MethodGet, "/files/*path", 1},
+		{"", "/static", 1},
+		{http.MethodGet, "/tenants/:tenant/", 3},
+		{http.MethodDelete, "/tenants/:tenant/projects/:project", 3},
+		{http.MethodGet, "/users/me", 1},
+		{http.MethodGet, "/users/:id", 1},
+		{http.MethodPost, "/users/:id", 1},
+	}
+
+	routes := router.Routes()
+
+	if len(routes) != len(expected) {
+		t.Fatalf("route count is %d and should be %d: %v", len(routes), len(expected), routes)
+	}
+
+	for i, r := range routes {
+		if r.Method != expected[i].method || r.Pattern != expected[i].pattern || len(r.Middleware) != expected[i].middleware {
+			t.Errorf("route %d is '%s %s' with %d middleware and should be '%s %s' with %d middleware", i, r.Method, r.Pattern, len(r.Middleware), expected[i].method, expected[i].pattern, expected[i].middleware)
+		}
+
+		if r.Handler == nil {
+			t.Errorf("handler of route '%s %s' should not be nil", r.Method, r.Pattern)
+		}
+	}
+
+	errWalk := errors.New("walk aborted")
+	count := 0
+
+	err := router.Walk(func(method string, pattern string, handler http.Handler, middleware []Middleware) error {
+		count++
+		return errWalk
+	})
+
+	if err != errWalk || count != 1 {
+		t.Errorf("walk should be aborted after first route, error is: %v, count is: %d", err, count)
+	}
+}
@@ -0,0 +1,300 @@
+package lionrouter
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ResponseWriter
+// interface is a http.ResponseWriter recording the status, the bytes and the time of the first write of the response
+type ResponseWriter interface {
+	http.ResponseWriter
+
+	// Status returns the status code of the response, 0 if the header is not written yet
+	Status() int
+
+	// BytesWritten returns the count of body bytes written
+	BytesWritten() int64
+
+	// FirstWrite returns the time the header or body was written first, zero if nothing is written yet
+	FirstWrite() time.Time
+
+	// Unwrap returns the wrapped writer, used by http.ResponseController
+	Unwrap() http.ResponseWriter
+}
+
+// WrapResponseWriter wraps the given writer to record the response
+// The returned writer implements the optional interfaces http.Flusher, http.Hijacker, io.ReaderFrom and http.Pusher
+// if and only if the given writer implements them
+func WrapResponseWriter(w http.ResponseWriter) ResponseWriter {
+	return newResponseWriter(w).wrapped
+}
+
+// responseWriter
+// struct records the response written through the wrapped writer
+type responseWriter struct {
+	w http.ResponseWriter
+
+	// writer exposing the optional interfaces of the wrapped writer
+	wrapped ResponseWriter
+
+	// header or body written, or connection hijacked
+	started bool
+
+	// status code of the response, 0 if the header is not written yet
+	status int
+
+	// count of body bytes written
+	bytes int64
+
+	// time of the first write
+	firstWrite time.Time
+}
+
+const (
+	// optional interfaces of the wrapped writer
+	wrapFlusher = 1 << iota
+	wrapHijacker
+	wrapReaderFrom
+	wrapPusher
+)
+
+func newResponseWriter(rw http.ResponseWriter) *responseWriter {
+	w := &responseWriter{w: rw}
+
+	// bit set of the implemented optional interfaces
+	implemented := 0
+
+	if _, ok := rw.(http.Flusher); ok {
+		implemented |= wrapFlusher
+	}
+
+	if _, ok := rw.(http.Hijacker); ok {
+		implemented |= wrapHijacker
+	}
+
+	if _, ok := rw.(io.ReaderFrom); ok {
+		implemented |= wrapReaderFrom
+	}
+
+	if _, ok := rw.(http.Pusher); ok {
+		implemented |= wrapPusher
+	}
+
+	switch implemented {
+	case 0:
+		w.wrapped = w
+	case wrapFlusher: // Flusher
+		w.wrapped = struct {
+			*responseWriter
+			flushWriter
+		}{w, flushWriter{w}}
+	case wrapHijacker: // Hijacker
+		w.wrapped = struct {
+			*responseWriter
+			hijackWriter
+		}{w, hijackWriter{w}}
+	case wrapFlusher | wrapHijacker: // Flusher + Hijacker
+		w.wrapped = struct {
+			*responseWriter
+			flushWriter
+			hijackWriter
+		}{w, flushWriter{w}, hijackWriter{w}}
+	case wrapReaderFrom: // ReaderFrom
+		w.wrapped = struct {
+			*responseWriter
+			readFromWriter
+		}{w, readFromWriter{w}}
+	case wrapFlusher | wrapReaderFrom: // Flusher + ReaderFrom
+		w.wrapped = struct {
+			*responseWriter
+			flushWriter
+			readFromWriter
+		}{w, flushWriter{w}, readFromWriter{w}}
+	case wrapHijacker | wrapReaderFrom: // Hijacker + ReaderFrom
+		w.wrapped = struct {
+			*responseWriter
+			hijackWriter
+			readFromWriter
+		}{w, hijackWriter{w}, readFromWriter{w}}
+	case wrapFlusher | wrapHijacker | wrapReaderFrom: // Flusher + Hijacker + ReaderFrom
+		w.wrapped = struct {
+			*responseWriter
+			flushWriter
+			hijackWriter
+			readFromWriter
+		}{w, flushWriter{w}, hijackWriter{w}, readFromWriter{w}}
+	case wrapPusher: // Pusher
+		w.wrapped = struct {
+			*responseWriter
+			pushWriter
+		}{w, pushWriter{w}}
+	case wrapFlusher | wrapPusher: // Flusher + Pusher
+		w.wrapped = struct {
+			*responseWriter
+			flushWriter
+			pushWriter
+		}{w, flushWriter{w}, pushWriter{w}}
+	case wrapHijacker | wrapPusher: // Hijacker + Pusher
+		w.wrapped = struct {
+			*responseWriter
+			hijackWriter
+			pushWriter
+		}{w, hijackWriter{w}, pushWriter{w}}
+	case wrapFlusher | wrapHijacker | wrapPusher: // Flusher + Hijacker + Pusher
+		w.wrapped = struct {
+			*responseWriter
+			flushWriter
+			hijackWriter
+			pushWriter
+		}{w, flushWriter{w}, hijackWriter{w}, pushWriter{w}}
+	case wrapReaderFrom | wrapPusher: // ReaderFrom + Pusher
+		w.wrapped = struct {
+			*responseWriter
+			readFromWriter
+			pushWriter
+		}{w, readFromWriter{w}, pushWriter{w}}
+	case wrapFlusher | wrapReaderFrom | wrapPusher: // Flusher + ReaderFrom + Pusher
+		w.wrapped = struct {
+			*responseWriter
+			flushWriter
+			readFromWriter
+			pushWriter
+		}{w, flushWriter{w}, readFromWriter{w}, pushWriter{w}}
+	case wrapHijacker | wrapReaderFrom | wrapPusher: // Hijacker + ReaderFrom + Pusher
+		w.wrapped = struct {
+			*responseWriter
+			hijackWriter
+			readFromWriter
+			pushWriter
+		}{w, hijackWriter{w}, readFromWriter{w}, pushWriter{w}}
+	case wrapFlusher | wrapHijacker | wrapReaderFrom | wrapPusher: // Flusher + Hijacker + ReaderFrom + Pusher
+		w.wrapped = struct {
+			*responseWriter
+			flushWriter
+			hijackWriter
+			readFromWriter
+			pushWriter
+		}{w, flushWriter{w}, hijackWriter{w}, readFromWriter{w}, pushWriter{w}}
+	}
+
+	return w
+}
+
+// start records the status code of the response once the header is written
+func (w *responseWriter) start(statusCode int) {
+	if !w.started {
+		w.started = true
+		w.status = statusCode
+		w.firstWrite = time.Now()
+	}
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.w.Header()
+}
+
+// WriteHeader writes the header, informational headers don't start the response
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if statusCode >= 200 {
+		w.start(statusCode)
+	}
+
+	w.w.WriteHeader(statusCode)
+}
+
+// Write writes the body, the header is written implicitly if not written yet
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.start(http.StatusOK)
+
+	n, err := w.w.Write(b)
+	w.bytes += int64(n)
+
+	return n, err
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) BytesWritten() int64 {
+	return w.bytes
+}
+
+func (w *responseWriter) FirstWrite() time.Time {
+	return w.firstWrite
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.w
+}
+
+// servedStatus retrieves the status code of the response once the handler returned,
+// 200 if nothing is written and 500 if not served due to a panic recovered by a parent router
+func (w *responseWriter) servedStatus(served bool) int {
+	if w.status != 0 {
+		return w.status
+	}
+
+	if !served {
+		return http.StatusInternalServerError
+	}
+
+	return http.StatusOK
+}
+
+// flushWriter
+// struct forwards http.Flusher to the wrapped writer, flushing writes the header implicitly
+type flushWriter struct {
+	w *responseWriter
+}
+
+func (f flushWriter) Flush() {
+	f.w.start(http.StatusOK)
+	f.w.w.(http.Flusher).Flush()
+}
+
+// hijackWriter
+// struct forwards http.Hijacker to the wrapped writer
+type hijackWriter struct {
+	w *responseWriter
+}
+
+func (h hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := h.w.w.(http.Hijacker).Hijack()
+
+	// the connection is taken over, nothing can be written by the router anymore
+	if err == nil {
+		h.w.started = true
+	}
+
+	return conn, rw, err
+}
+
+// readFromWriter
+// struct forwards io.ReaderFrom to the wrapped writer, the bytes read are recorded
+type readFromWriter struct {
+	w *responseWriter
+}
+
+func (r readFromWriter) ReadFrom(src io.Reader) (int64, error) {
+	r.w.start(http.StatusOK)
+
+	n, err := r.w.w.(io.ReaderFrom).ReadFrom(src)
+	r.w.bytes += n
+
+	return n, err
+}
+
+// pushWriter
+// struct forwards http.Pusher to the wrapped writer
+type pushWriter struct {
+	w *responseWriter
+}
+
+func (p pushWriter) Push(target string, opts *http.PushOptions) error {
+	return p.w.w.(http.Pusher).Push(target, opts)
+}
@@ -0,0 +1,226 @@
+package lionrouter
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseWriterStarted(t *testing.T) {
+	tests := []struct {
+		write   func(w http.ResponseWriter)
+		started bool
+		status  int
+	}{
+		{func(w http.ResponseWriter) {}, false, 0},
+		{func(w http.ResponseWriter) { w.WriteHeader(http.StatusEarlyHints) }, false, 0},
+		{func(w http.ResponseWriter) { w.WriteHeader(http.StatusNoContent) }, true, http.StatusNoContent},
+		{func(w http.ResponseWriter) { w.Write([]byte("body")) }, true, http.StatusOK},
+		{func(w http.ResponseWriter) { w.(http.Flusher).Flush() }, true, http.StatusOK},
+		{func(w http.ResponseWriter) { w.(io.ReaderFrom).ReadFrom(strings.NewReader("body")) }, true, http.StatusOK},
+	}
+
+	for i, test := range tests {
+		w := newResponseWriter(readerFromRecorder{httptest.NewRecorder()})
+		test.write(w.wrapped)
+
+		if w.started != test.started || w.wrapped.Status() != test.status {
+			t.Errorf("writer %d started is %t with status %d and should be %t with %d", i, w.started, w.wrapped.Status(), test.started, test.status)
+		}
+
+		if test.started && w.wrapped.FirstWrite().IsZero() {
+			t.Errorf("writer %d first write should be set", i)
+		}
+	}
+}
+
+func TestWrapResponseWriter(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	w := WrapResponseWriter(readerFromRecorder{recorder})
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("abc"))
+	w.(io.ReaderFrom).ReadFrom(strings.NewReader("defg"))
+
+	if w.Status() != http.StatusCreated || w.BytesWritten() != 7 || recorder.Body.String() != "abcdefg" {
+		t.Errorf("writer recorded status %d and %d bytes, body is '%s'", w.Status(), w.BytesWritten(), recorder.Body.String())
+	}
+
+	if err := http.NewResponseController(w).Flush(); err != nil {
+		t.Errorf("response controller should reach the wrapped writer: %v", err)
+	}
+
+	// every combination of optional interfaces is forwarded, but never added
+	for implemented := 0; implemented < 16; implemented++ {
+		inner := optionalWriter(implemented)
+		wrapped := WrapResponseWriter(inner)
+
+		_, flusher := wrapped.(http.Flusher)
+		_, hijacker := wrapped.(http.Hijacker)
+		_, readerFrom := wrapped.(io.ReaderFrom)
+		_, pusher := wrapped.(http.Pusher)
+
+		if flusher != (implemented&wrapFlusher != 0) || hijacker != (implemented&wrapHijacker != 0) ||
+			readerFrom != (implemented&wrapReaderFrom != 0) || pusher != (implemented&wrapPusher != 0) {
+			t.Errorf("writer %04b implements flusher %t, hijacker %t, reader from %t, pusher %t", implemented, flusher, hijacker, readerFrom, pusher)
+		}
+
+		if wrapped.Unwrap() != inner {
+			t.Errorf("writer %04b should unwrap to the wrapped writer", implemented)
+		}
+	}
+}
+
+// readerFromRecorder
+// struct adds io.ReaderFrom to the recorder
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r readerFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	return io.Copy(r.ResponseRecorder, src)
+}
+
+type baseWriter struct{ http.ResponseWriter }
+type flushOnly struct{}
+type hijackOnly struct{}
+type readFromOnly struct{}
+type pushOnly struct{}
+
+func (flushOnly) Flush() {}
+func (hijackOnly) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, nil
+}
+func (readFromOnly) ReadFrom(io.Reader) (int64, error) {
+	return 0, nil
+}
+func (pushOnly) Push(string, *http.PushOptions) error {
+	return nil
+}
+
+// optionalWriter creates a writer implementing the optional interfaces of the given bit set
+func optionalWriter(implemented int) http.ResponseWriter {
+	base := baseWriter{httptest.NewRecorder()}
+
+	switch implemented {
+	case 0:
+		return base
+	case 1:
+		return struct {
+			baseWriter
+			flushOnly
+		}{base, flushOnly{}}
+	case 2:
+		return struct {
+			baseWriter
+			hijackOnly
+		}{base, hijackOnly{}}
+	case 3:
+		return struct {
+			baseWriter
+			flushOnly
+			hijackOnly
+		}{base, flushOnly{}, hijackOnly{}}
+	case 4:
+		return struct {
+			baseWriter
+			readFromOnly
+		}{base, readFromOnly{}}
+	case 5:
+		return struct {
+			baseWriter
+			flushOnly
+			readFromOnly
+		}{base, flushOnly{}, readFromOnly{}}
+	case 6:
+		return struct {
+			baseWriter
+			hijackOnly
+			readFromOnly
+		}{base, hijackOnly{}, readFromOnly{}}
+	case 7:
+		return struct {
+			baseWriter
+			flushOnly
+			hijackOnly
+			readFromOnly
+		}{base, flushOnly{}, hijackOnly{}, readFromOnly{}}
+	case 8:
+		return struct {
+			baseWriter
+			pushOnly
+		}{base, pushOnly{}}
+	case 9:
+		return struct {
+			baseWriter
+			flushOnly
+			pushOnly
+		}{base, flushOnly{}, pushOnly{}}
+	case 10:
+		return struct {
+			baseWriter
+			hijackOnly
+			pushOnly
+		}{base, hijackOnly{}, pushOnly{}}
+	case 11:
+		return struct {
+			baseWriter
+			flushOnly
+			hijackOnly
+			pushOnly
+		}{base, flushOnly{}, hijackOnly{}, pushOnly{}}
+	case 12:
+		return struct {
+			baseWriter
+			readFromOnly
+			pushOnly
+		}{base, readFromOnly{}, pushOnly{}}
+	case 13:
+		return struct {
+			baseWriter
+			flushOnly
+			readFromOnly
+			pushOnly
+		}{base, flushOnly{}, readFromOnly{}, pushOnly{}}
+	case 14:
+		return struct {
+			baseWriter
+			hijackOnly
+			readFromOnly
+			pushOnly
+		}{base, hijackOnly{}, readFromOnly{}, pushOnly{}}
+	case 15:
+		return struct {
+			baseWriter
+			flushOnly
+			hijackOnly
+			readFromOnly
+			pushOnly
+		}{base, flushOnly{}, hijackOnly{}, readFromOnly{}, pushOnly{}}
+	}
+
+	return nil
+}
+
+func TestRouterResponseWriter(t *testing.T) {
+	router := New()
+	router.Metrics = NewMetrics()
+	router.Tracer = NewMemoryTracer(nil)
+
+	router.Get("/stream", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, flusher := w.(http.Flusher)
+		_, hijacker := w.(http.Hijacker)
+
+		if !flusher || hijacker {
+			t.Errorf("handler writer implements flusher %t and hijacker %t, should be true and false", flusher, hijacker)
+		}
+	}))
+
+	handler := AccessLog(AccessLogOptions{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})(router)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/stream", nil))
+}